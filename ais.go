@@ -0,0 +1,210 @@
+package nmea
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// AISPosition is a vessel position report decoded from an AIS
+// message: types 1, 2 and 3 (class A) or 18 (class B).
+type AISPosition struct {
+	MMSI                uint32
+	Latitude, Longitude float64
+	// Speed over ground in knots, or 102.3 if not available.
+	Speed float64
+	// Course over ground in degrees, or 360 if not available.
+	Course float64
+	// Heading in degrees, or 511 if not available.
+	Heading int
+}
+
+// An AISPositionHandler handles AIS position reports from VDM and VDO
+// sentences.
+type AISPositionHandler interface {
+	HandleAISPosition(AISPosition)
+}
+
+// aisBits is an AIS payload, de-armored into six bit groups.
+type aisBits []byte
+
+func decodeAISPayload(s string) (aisBits, error) {
+	b := make(aisBits, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > 'w' || (c > 'W' && c < '`') {
+			return nil, &ParseError{"payload", s, "invalid character"}
+		}
+		c -= '0'
+		if c > 40 {
+			c -= 8
+		}
+		b[i] = c
+	}
+	return b, nil
+}
+
+func (b aisBits) uint(start, n int) uint32 {
+	var rv uint32
+	for i := start; i < start+n; i++ {
+		rv = rv<<1 | uint32(b[i/6]>>(5-uint(i%6))&1)
+	}
+	return rv
+}
+
+func (b aisBits) int(start, n int) int32 {
+	v := b.uint(start, n)
+	if v&(1<<uint(n-1)) != 0 {
+		v |= ^uint32(0) << uint(n)
+	}
+	return int32(v)
+}
+
+/*
+	!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C
+
+Where:
+
+	1: 1         Number of fragments
+	2: 1         Fragment number
+	3: (empty)   Sequential message ID for multi-fragment messages
+	4: B         Radio channel
+	5: 177K...   Payload, six bits per character
+	6: 0         Number of fill bits
+
+VDO has the same layout, but reports the receiving vessel itself.
+Only single fragment position reports are decoded.
+*/
+func vdmParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(AISPositionHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 7 {
+		return errShortMsg
+	}
+
+	if parts[1] != "1" {
+		return nil
+	}
+
+	b, err := decodeAISPayload(parts[5])
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return errShortMsg
+	}
+
+	var pos AISPosition
+	switch typ := b.uint(0, 6); typ {
+	case 1, 2, 3:
+		if len(b)*6 < 137 {
+			return errShortMsg
+		}
+		pos = AISPosition{
+			MMSI:      b.uint(8, 30),
+			Speed:     float64(b.uint(50, 10)) / 10,
+			Longitude: float64(b.int(61, 28)) / 600000,
+			Latitude:  float64(b.int(89, 27)) / 600000,
+			Course:    float64(b.uint(116, 12)) / 10,
+			Heading:   int(b.uint(128, 9)),
+		}
+	case 18:
+		if len(b)*6 < 133 {
+			return errShortMsg
+		}
+		pos = AISPosition{
+			MMSI:      b.uint(8, 30),
+			Speed:     float64(b.uint(46, 10)) / 10,
+			Longitude: float64(b.int(57, 28)) / 600000,
+			Latitude:  float64(b.int(85, 27)) / 600000,
+			Course:    float64(b.uint(112, 12)) / 10,
+			Heading:   int(b.uint(124, 9)),
+		}
+	default:
+		return nil
+	}
+
+	h.HandleAISPosition(pos)
+
+	return nil
+}
+
+// AISRegistry keeps the latest position of each vessel heard over
+// AIS, turning a stream into a table of vessels that may be queried
+// from any goroutine.
+type AISRegistry struct {
+	// TTL is how long a vessel is kept after its last report.
+	// Zero keeps vessels forever.
+	TTL time.Duration
+
+	now func() time.Time
+
+	mu      sync.Mutex
+	vessels map[uint32]aisEntry
+}
+
+type aisEntry struct {
+	pos  AISPosition
+	seen time.Time
+}
+
+func (r *AISRegistry) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// HandleAISPosition satisfies AISPositionHandler.
+func (r *AISRegistry) HandleAISPosition(pos AISPosition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.vessels == nil {
+		r.vessels = map[uint32]aisEntry{}
+	}
+	r.vessels[pos.MMSI] = aisEntry{pos, r.clock()}
+}
+
+// expire removes vessels not heard from within the TTL.  r.mu must be
+// held.
+func (r *AISRegistry) expire() {
+	if r.TTL == 0 {
+		return
+	}
+	now := r.clock()
+	for mmsi, e := range r.vessels {
+		if now.Sub(e.seen) > r.TTL {
+			delete(r.vessels, mmsi)
+		}
+	}
+}
+
+// Get returns the latest position of the given vessel.
+func (r *AISRegistry) Get(mmsi uint32) (AISPosition, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire()
+	e, ok := r.vessels[mmsi]
+	return e.pos, ok
+}
+
+// All returns the latest position of every vessel, ordered by MMSI.
+func (r *AISRegistry) All() []AISPosition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire()
+	rv := make([]AISPosition, 0, len(r.vessels))
+	for _, e := range r.vessels {
+		rv = append(rv, e.pos)
+	}
+	sort.Slice(rv, func(i, j int) bool { return rv[i].MMSI < rv[j].MMSI })
+	return rv
+}
+
+// Position returns the position of the vessel.
+func (p AISPosition) Position() Position {
+	return Position{p.Latitude, p.Longitude}
+}
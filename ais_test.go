@@ -0,0 +1,133 @@
+package nmea
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type aisHandler struct {
+	pos AISPosition
+}
+
+func (a *aisHandler) HandleAISPosition(pos AISPosition) {
+	a.pos = pos
+}
+
+// aisSentence armors fields of the given widths into a VDM sentence.
+func aisSentence(fields ...[2]int64) string {
+	var bits []byte
+	for _, f := range fields {
+		for i := f[1] - 1; i >= 0; i-- {
+			bits = append(bits, byte(f[0]>>uint(i)&1))
+		}
+	}
+	for len(bits)%6 != 0 {
+		bits = append(bits, 0)
+	}
+	var payload []byte
+	for i := 0; i < len(bits); i += 6 {
+		c := bits[i]<<5 | bits[i+1]<<4 | bits[i+2]<<3 | bits[i+3]<<2 | bits[i+4]<<1 | bits[i+5]
+		c += '0'
+		if c > 'W' {
+			c += 8
+		}
+		payload = append(payload, c)
+	}
+	s := fmt.Sprintf("!AIVDM,1,1,,A,%s,0", payload)
+	return fmt.Sprintf("%s*%02X", s, Checksum(s))
+}
+
+func TestAISPositionHandling(t *testing.T) {
+	classB := aisSentence(
+		[2]int64{18, 6}, [2]int64{0, 2}, [2]int64{338087471, 30}, [2]int64{0, 8},
+		[2]int64{1, 10}, [2]int64{0, 1},
+		[2]int64{int64(-74.0 * 600000), 28}, [2]int64{int64(40.5 * 600000), 27},
+		[2]int64{797, 12}, [2]int64{511, 9}, [2]int64{0, 35},
+	)
+
+	tests := []struct {
+		in  string
+		exp AISPosition
+	}{
+		{"!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C",
+			AISPosition{477553000, 47.582833, -122.345832, 0, 51, 181}},
+		{classB, AISPosition{338087471, 40.5, -74, 0.1, 79.7, 511}},
+	}
+
+	for _, test := range tests {
+		h := &aisHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if !similar(t, h.pos, test.exp) {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.pos)
+		}
+	}
+
+	// Multi-fragment messages and other message types are skipped.
+	skipped := []string{
+		"!AIVDM,2,1,3,B,55P5TL01VIaAL@7WKO@mBplU@<PDhh000000001S;AJ::4A80?4i@E53,0*3E",
+		aisSentence([2]int64{5, 6}, [2]int64{0, 2}, [2]int64{338087471, 30}),
+	}
+	for _, in := range skipped {
+		h := &aisHandler{}
+		if err := (&Processor{}).parseMessage(in, h); err != nil || h.pos.MMSI != 0 {
+			t.Errorf("Expected %q to be skipped, got %v, %+v", in, err, h.pos)
+		}
+	}
+
+	bad := []string{
+		"!AIVDM,1,1,,B,177KQJ5000G?tO`K>R,0*45",
+		"!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TK~,0*6A",
+	}
+	for _, in := range bad {
+		if err := (&Processor{}).parseMessage(in, &aisHandler{}); err == nil {
+			t.Errorf("Expected error parsing %q", in)
+		}
+	}
+}
+
+func TestAISRegistry(t *testing.T) {
+	now := time.Date(2017, 4, 1, 12, 0, 0, 0, time.UTC)
+	r := &AISRegistry{TTL: time.Minute, now: func() time.Time { return now }}
+
+	input := "!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C\n" +
+		aisSentence(
+			[2]int64{1, 6}, [2]int64{0, 2}, [2]int64{366053209, 30}, [2]int64{0, 12},
+			[2]int64{100, 10}, [2]int64{0, 1},
+			[2]int64{int64(-122.4 * 600000), 28}, [2]int64{int64(37.8 * 600000), 27},
+			[2]int64{900, 12}, [2]int64{90, 9}, [2]int64{0, 31},
+		) + "\n"
+	if err := Process(strings.NewReader(input), r, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	pos, ok := r.Get(366053209)
+	if !ok || !near(pos.Latitude, 37.8) || pos.Speed != 10 || pos.Heading != 90 {
+		t.Errorf("Unexpected position for 366053209: %+v, %v", pos, ok)
+	}
+	if _, ok := r.Get(1); ok {
+		t.Errorf("Unexpected position for unknown vessel")
+	}
+	var mmsis []uint32
+	for _, p := range r.All() {
+		mmsis = append(mmsis, p.MMSI)
+	}
+	if exp := []uint32{366053209, 477553000}; !reflect.DeepEqual(mmsis, exp) {
+		t.Errorf("Expected vessels %v, got %v", exp, mmsis)
+	}
+
+	now = now.Add(30 * time.Second)
+	r.HandleAISPosition(AISPosition{MMSI: 477553000})
+	now = now.Add(45 * time.Second)
+	if _, ok := r.Get(366053209); ok {
+		t.Errorf("Expected 366053209 to have expired")
+	}
+	if all := r.All(); len(all) != 1 || all[0].MMSI != 477553000 {
+		t.Errorf("Expected only 477553000 to remain, got %+v", all)
+	}
+}
@@ -0,0 +1,86 @@
+package nmea
+
+import "math"
+
+// weightedStat accumulates a weighted mean and variance.
+type weightedStat struct {
+	w, mean, s float64
+}
+
+func (ws *weightedStat) add(x, weight float64) {
+	ws.w += weight
+	d := x - ws.mean
+	ws.mean += weight / ws.w * d
+	ws.s += weight * d * (x - ws.mean)
+}
+
+func (ws *weightedStat) stddev() float64 {
+	if ws.w == 0 {
+		return 0
+	}
+	return math.Sqrt(ws.s / ws.w)
+}
+
+// PositionAverager averages the fixes of a stationary receiver, as in
+// a static survey, to reduce their error.
+//
+// GGA fixes are weighted by the inverse of their horizontal dilution
+// of precision, so better fixes count for more.  RMC fixes, which
+// carry neither dilution nor altitude, are weighted as if their HDOP
+// were 1.  Since most receivers report every fix in both, it's
+// usually best to feed only one of them.  Invalid fixes are ignored.
+type PositionAverager struct {
+	lat, lon, alt weightedStat
+	count         int
+}
+
+// HandleGGA satisfies GGAHandler.
+func (a *PositionAverager) HandleGGA(g GGA) {
+	if !g.Valid() {
+		return
+	}
+	w := 1.0
+	if g.HorizontalDilution > 0 {
+		w = 1 / g.HorizontalDilution
+	}
+	a.add(g.Latitude, g.Longitude, w)
+	a.alt.add(g.Altitude, w)
+}
+
+// HandleRMC satisfies RMCHandler.
+func (a *PositionAverager) HandleRMC(r RMC) {
+	if !r.Valid() {
+		return
+	}
+	a.add(r.Latitude, r.Longitude, 1)
+}
+
+func (a *PositionAverager) add(lat, lon, w float64) {
+	a.lat.add(lat, w)
+	a.lon.add(lon, w)
+	a.count++
+}
+
+// Count returns the number of fixes averaged.
+func (a *PositionAverager) Count() int {
+	return a.count
+}
+
+// Mean returns the weighted mean position.
+func (a *PositionAverager) Mean() Position {
+	return Position{a.lat.mean, a.lon.mean}
+}
+
+// MeanAltitude returns the weighted mean GGA altitude in meters.
+func (a *PositionAverager) MeanAltitude() float64 {
+	return a.alt.mean
+}
+
+// StdDev returns the weighted standard deviation of the fixes north,
+// east and up, in meters.
+func (a *PositionAverager) StdDev() (north, east, up float64) {
+	perDegree := d2r(1) * earthRadius
+	return a.lat.stddev() * perDegree,
+		a.lon.stddev() * perDegree * math.Cos(d2r(a.lat.mean)),
+		a.alt.stddev()
+}
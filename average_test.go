@@ -0,0 +1,61 @@
+package nmea
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPositionAverager(t *testing.T) {
+	centroid := Position{37.383806, -121.989975}
+	r := rand.New(rand.NewSource(42))
+
+	a := &PositionAverager{}
+	for i := 0; i < 10000; i++ {
+		// Roughly ±5m of jitter.
+		a.HandleGGA(GGA{
+			Latitude:           centroid.Lat + (r.Float64()-0.5)*0.0001,
+			Longitude:          centroid.Lon + (r.Float64()-0.5)*0.0001,
+			Altitude:           100 + (r.Float64()-0.5)*10,
+			Quality:            GPSFix,
+			HorizontalDilution: 1 + r.Float64(),
+		})
+	}
+	a.HandleGGA(GGA{Latitude: 10, Longitude: 10, Quality: InvalidFix})
+	a.HandleRMC(RMC{Latitude: 10, Longitude: 10, Status: 'V'})
+
+	if a.Count() != 10000 {
+		t.Errorf("Expected 10000 fixes, got %v", a.Count())
+	}
+	if d := a.Mean().DistanceTo(centroid); d > 0.1 {
+		t.Errorf("Expected mean near %v, got %v (%.2fm away)", centroid, a.Mean(), d)
+	}
+	if math.Abs(a.MeanAltitude()-100) > 0.1 {
+		t.Errorf("Expected mean altitude near 100, got %v", a.MeanAltitude())
+	}
+
+	// Uniform jitter over w has a stddev of w/√12.
+	n, e, u := a.StdDev()
+	for _, test := range []struct {
+		name     string
+		got, exp float64
+	}{
+		{"north", n, 11.119 / math.Sqrt(12)},
+		{"east", e, 11.119 * math.Cos(d2r(centroid.Lat)) / math.Sqrt(12)},
+		{"up", u, 10 / math.Sqrt(12)},
+	} {
+		if math.Abs(test.got-test.exp) > 0.1 {
+			t.Errorf("Expected %v stddev near %.2f, got %.2f", test.name, test.exp, test.got)
+		}
+	}
+}
+
+func TestPositionAveragerWeights(t *testing.T) {
+	a := &PositionAverager{}
+	a.HandleGGA(GGA{Latitude: 1, Longitude: 1, Quality: GPSFix, HorizontalDilution: 1})
+	a.HandleGGA(GGA{Latitude: 4, Longitude: 4, Quality: GPSFix, HorizontalDilution: 2})
+	a.HandleRMC(RMC{Latitude: 1, Longitude: 1, Status: 'A'})
+	if exp := (Position{1.6, 1.6}); !a.Mean().Equal(exp, 0.01) {
+		t.Errorf("Expected weighted mean %v, got %v", exp, a.Mean())
+	}
+}
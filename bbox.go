@@ -0,0 +1,71 @@
+package nmea
+
+import "math"
+
+// BoundingBox accumulates the extent of the valid fixes in a stream,
+// e.g. to zoom a map to fit a track.
+//
+// Longitudes are compared numerically, so a track crossing the
+// antimeridian yields a box spanning nearly every longitude, west of
+// its western end to east of its eastern end the long way round,
+// rather than the narrow box across 180°.
+type BoundingBox struct {
+	minLat, minLon, maxLat, maxLon float64
+	n                              int
+}
+
+// HandleRMC satisfies RMCHandler.
+func (b *BoundingBox) HandleRMC(r RMC) {
+	if r.Valid() {
+		b.Add(r.Position())
+	}
+}
+
+// HandleGGA satisfies GGAHandler.
+func (b *BoundingBox) HandleGGA(g GGA) {
+	if g.Valid() {
+		b.Add(g.Position())
+	}
+}
+
+// HandleGLL satisfies GLLHandler.
+func (b *BoundingBox) HandleGLL(g GLL) {
+	if g.Valid() {
+		b.Add(g.Position())
+	}
+}
+
+// Add extends the box to include p.
+func (b *BoundingBox) Add(p Position) {
+	if b.n == 0 {
+		b.minLat, b.maxLat = p.Lat, p.Lat
+		b.minLon, b.maxLon = p.Lon, p.Lon
+	}
+	b.n++
+	b.minLat = math.Min(b.minLat, p.Lat)
+	b.maxLat = math.Max(b.maxLat, p.Lat)
+	b.minLon = math.Min(b.minLon, p.Lon)
+	b.maxLon = math.Max(b.maxLon, p.Lon)
+}
+
+// Empty reports whether no fixes have been added.  The corners of an
+// empty box are both at (0,0).
+func (b *BoundingBox) Empty() bool {
+	return b.n == 0
+}
+
+// NorthEast returns the north east corner of the box.
+func (b *BoundingBox) NorthEast() Position {
+	return Position{b.maxLat, b.maxLon}
+}
+
+// SouthWest returns the south west corner of the box.
+func (b *BoundingBox) SouthWest() Position {
+	return Position{b.minLat, b.minLon}
+}
+
+// Center returns the point midway between the box's extremes of
+// latitude and of longitude.
+func (b *BoundingBox) Center() Position {
+	return Position{(b.minLat + b.maxLat) / 2, (b.minLon + b.maxLon) / 2}
+}
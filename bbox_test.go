@@ -0,0 +1,52 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBoundingBox(t *testing.T) {
+	b := &BoundingBox{}
+	if !b.Empty() {
+		t.Errorf("Expected a new box to be empty")
+	}
+
+	fixes := []GGA{
+		{Latitude: 37.5, Longitude: -122.25, Quality: GPSFix},
+		{Latitude: 37.25, Longitude: -121.5, Quality: GPSFix},
+		{Latitude: 38, Longitude: -122, Quality: DGPSFix},
+		{Latitude: 0, Longitude: 0, Quality: InvalidFix},
+	}
+	for _, g := range fixes {
+		b.HandleGGA(g)
+	}
+	b.HandleRMC(RMC{Status: 'A', Latitude: 37, Longitude: -122})
+	b.HandleRMC(RMC{Status: 'V'})
+	b.HandleGLL(GLL{Active: true, Latitude: 37.75, Longitude: -122.5})
+	b.HandleGLL(GLL{})
+
+	if b.Empty() {
+		t.Errorf("Expected a non-empty box")
+	}
+	if got, exp := b.NorthEast(), (Position{38, -121.5}); got != exp {
+		t.Errorf("Expected north east corner %v, got %v", exp, got)
+	}
+	if got, exp := b.SouthWest(), (Position{37, -122.5}); got != exp {
+		t.Errorf("Expected south west corner %v, got %v", exp, got)
+	}
+	if got, exp := b.Center(), (Position{37.5, -122}); got != exp {
+		t.Errorf("Expected center %v, got %v", exp, got)
+	}
+}
+
+func TestBoundingBoxProcess(t *testing.T) {
+	b := &BoundingBox{}
+	if err := Process(strings.NewReader(ubloxSample+freeNmeaSample), b, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	// The free NMEA sample's fixes are all void.
+	p := Position{37.383806166666666, -121.9899755}
+	if !b.NorthEast().Equal(p, 0.01) || !b.SouthWest().Equal(p, 0.01) {
+		t.Errorf("Expected a box around %v, got %v-%v", p, b.SouthWest(), b.NorthEast())
+	}
+}
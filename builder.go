@@ -0,0 +1,233 @@
+package nmea
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A SentenceBuilder writes well-formed NMEA sentences to an
+// io.Writer, e.g. to emulate a device.
+type SentenceBuilder struct {
+	w io.Writer
+	// Talker is the talker ID prefixed to each sentence type.
+	Talker string
+}
+
+// NewSentenceBuilder returns a SentenceBuilder writing GP sentences
+// to w.
+func NewSentenceBuilder(w io.Writer) *SentenceBuilder {
+	return &SentenceBuilder{w: w, Talker: "GP"}
+}
+
+// Build returns the complete sentence (including checksum and CRLF)
+// for the given sentence type and fields.
+//
+// Fields may not contain any of the NMEA delimiters.
+func (b *SentenceBuilder) Build(typ string, fields []string) (string, error) {
+	return buildSentence(b.Talker+typ, fields)
+}
+
+// Write writes the sentence for the given type and fields.
+func (b *SentenceBuilder) Write(typ string, fields []string) error {
+	s, err := b.Build(typ, fields)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(b.w, s)
+	return err
+}
+
+func buildSentence(addr string, fields []string) (string, error) {
+	buf := &strings.Builder{}
+	buf.WriteByte('$')
+	buf.WriteString(addr)
+	for _, f := range fields {
+		if strings.ContainsAny(f, ",*$!\r\n") {
+			return "", fmt.Errorf("invalid character in %v field %q", addr, f)
+		}
+		buf.WriteByte(',')
+		buf.WriteString(f)
+	}
+	return AppendChecksum(buf.String()) + "\r\n", nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatDMS formats a coordinate as NMEA degrees and minutes along
+// with its hemisphere, the inverse of parseDMS.
+func formatDMS(deg float64, lon bool) (string, string) {
+	ref, width := "N", 2
+	switch {
+	case lon && deg < 0:
+		ref, width = "W", 3
+	case lon:
+		ref, width = "E", 3
+	case deg < 0:
+		ref = "S"
+	}
+	m := math.Round(math.Abs(deg)*60*1e5) / 1e5
+	d := math.Floor(m / 60)
+	return fmt.Sprintf("%0*d%08.5f", width, int(d), m-d*60), ref
+}
+
+// Marshal returns the fields of an RMC sentence describing r.
+func (r RMC) Marshal() []string {
+	lat, latRef := formatDMS(r.Latitude, false)
+	lon, lonRef := formatDMS(r.Longitude, true)
+	status := "V"
+	if r.Status != 0 {
+		status = string(r.Status)
+	}
+	magvar, magRef := "", ""
+	if r.Magvar != 0 {
+		magvar, magRef = formatFloat(math.Abs(r.Magvar)), "E"
+		if r.Magvar < 0 {
+			magRef = "W"
+		}
+	}
+	fields := []string{
+		r.Timestamp.Format("150405.00"),
+		status,
+		lat, latRef,
+		lon, lonRef,
+		formatFloat(r.Speed),
+		formatFloat(r.Angle),
+		r.Timestamp.Format("020106"),
+		magvar, magRef,
+	}
+	if r.Mode != 0 || r.ExtraFields != nil {
+		fields = append(fields, formatMode(r.Mode))
+	}
+	return append(fields, r.ExtraFields...)
+}
+
+// Marshal returns the fields of a GGA sentence describing g.
+func (g GGA) Marshal() []string {
+	lat, latRef := formatDMS(g.Latitude, false)
+	lon, lonRef := formatDMS(g.Longitude, true)
+	fields := []string{
+		g.Taken.Format("150405.00"),
+		lat, latRef,
+		lon, lonRef,
+		strconv.Itoa(int(g.Quality)),
+		fmt.Sprintf("%02d", g.NumSats),
+		formatFloat(g.HorizontalDilution),
+		formatFloat(g.Altitude), "M",
+		formatFloat(g.GeoidHeight), "M",
+		"", "",
+	}
+	return append(fields, g.ExtraFields...)
+}
+
+// formatMode formats an optional mode indicator.
+func formatMode(m Mode) string {
+	if m == 0 {
+		return ""
+	}
+	return string(rune(m))
+}
+
+// Marshal returns the fields of a GLL sentence describing g.
+func (g GLL) Marshal() []string {
+	lat, latRef := formatDMS(g.Latitude, false)
+	lon, lonRef := formatDMS(g.Longitude, true)
+	status := "V"
+	if g.Active {
+		status = "A"
+	}
+	fields := []string{
+		lat, latRef,
+		lon, lonRef,
+		g.Taken.Format("150405.00"),
+		status,
+	}
+	if g.Mode != 0 || g.ExtraFields != nil {
+		fields = append(fields, formatMode(g.Mode))
+	}
+	return append(fields, g.ExtraFields...)
+}
+
+// Marshal returns the fields of a VTG sentence describing v.  The
+// speeds are left empty if v isn't Valid and they're zero.
+func (v VTG) Marshal() []string {
+	knots, kmh := formatFloat(v.Knots), formatFloat(v.KMH)
+	if !v.Valid && v.Knots == 0 && v.KMH == 0 {
+		knots, kmh = "", ""
+	}
+	fields := []string{
+		formatFloat(v.True), "T",
+		formatFloat(v.Magnetic), "M",
+		knots, "N",
+		kmh, "K",
+	}
+	if v.Mode != 0 || v.ExtraFields != nil {
+		fields = append(fields, formatMode(v.Mode))
+	}
+	return append(fields, v.ExtraFields...)
+}
+
+// Marshal returns the fields of a GSA sentence describing g.  Only
+// the first 12 satellites used fit.
+func (g GSA) Marshal() []string {
+	auto := "M"
+	if g.Auto {
+		auto = "A"
+	}
+	fields := []string{auto, strconv.Itoa(int(g.Fix))}
+	for i := 0; i < 12; i++ {
+		prn := ""
+		if i < len(g.SatsUsed) {
+			prn = fmt.Sprintf("%02d", g.SatsUsed[i])
+		}
+		fields = append(fields, prn)
+	}
+	fields = append(fields, formatFloat(g.PDOP), formatFloat(g.HDOP), formatFloat(g.VDOP))
+	if g.SystemID != 0 {
+		fields = append(fields, strconv.Itoa(g.SystemID))
+	}
+	return fields
+}
+
+// Marshal returns the fields of a GSV sentence describing g.  A zero
+// SNR is left empty, as for satellites not being tracked.
+func (g GSV) Marshal() []string {
+	fields := []string{
+		strconv.Itoa(g.TotalSentences),
+		strconv.Itoa(g.SentenceNum),
+		fmt.Sprintf("%02d", g.InView),
+	}
+	for _, si := range g.SatInfo {
+		snr := ""
+		if si.SNR != 0 {
+			snr = fmt.Sprintf("%02d", si.SNR)
+		}
+		fields = append(fields,
+			fmt.Sprintf("%02d", si.PRN),
+			fmt.Sprintf("%02d", si.Elevation),
+			fmt.Sprintf("%03d", si.Azimuth),
+			snr)
+	}
+	if g.SignalID != 0 {
+		fields = append(fields, strconv.FormatInt(int64(g.SignalID), 16))
+	}
+	return fields
+}
+
+// Marshal returns the fields of a ZDA sentence describing z, with
+// the local zone of its Timestamp's location.
+func (z ZDA) Marshal() []string {
+	_, offset := z.Timestamp.Zone()
+	return []string{
+		z.Timestamp.Format("150405.00"),
+		z.Timestamp.Format("02"),
+		z.Timestamp.Format("01"),
+		fmt.Sprintf("%04d", z.Timestamp.Year()),
+		fmt.Sprintf("%02d", offset/3600),
+		fmt.Sprintf("%02d", offset%3600/60),
+	}
+}
@@ -0,0 +1,108 @@
+package nmea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChecksumFunc(t *testing.T) {
+	tests := map[string]byte{
+		"":    0,
+		"$":   0,
+		"$*":  0,
+		"A":   'A',
+		"$A*": 'A',
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74": 0x74,
+		"GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A":     0x74,
+	}
+	for in, exp := range tests {
+		if got := Checksum(in); got != exp {
+			t.Errorf("Checksum(%q) = %02X, want %02X", in, got, exp)
+		}
+	}
+}
+
+func TestFormatDMS(t *testing.T) {
+	tests := []struct {
+		in       float64
+		lon      bool
+		exp, ref string
+	}{
+		{37.383806166666666, false, "3723.02837", "N"},
+		{-121.9899755, true, "12159.39853", "W"},
+		{-37.383806166666666, false, "3723.02837", "S"},
+		{8.5, true, "00830.00000", "E"},
+		{0, false, "0000.00000", "N"},
+		{47.99999999999, false, "4800.00000", "N"},
+	}
+	for _, test := range tests {
+		got, ref := formatDMS(test.in, test.lon)
+		if got != test.exp || ref != test.ref {
+			t.Errorf("formatDMS(%v, %v) = %v,%v, want %v,%v", test.in, test.lon, got, ref, test.exp, test.ref)
+		}
+	}
+}
+
+func TestSentenceBuilder(t *testing.T) {
+	rmc := RMC{
+		Timestamp: time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC),
+		Status:    'A',
+		Latitude:  37.383806166666666,
+		Longitude: -121.9899755,
+		Speed:     0.82,
+		Angle:     188.36,
+		Magvar:    -3.1,
+	}
+	gga := GGA{
+		Taken:              time.Date(0, 1, 1, 16, 22, 54, 0, time.UTC),
+		Latitude:           37.383806166666666,
+		Longitude:          -121.9899755,
+		Quality:            GPSFix,
+		NumSats:            3,
+		HorizontalDilution: 2.36,
+		Altitude:           525.6,
+		GeoidHeight:        -25.6,
+	}
+
+	buf := &bytes.Buffer{}
+	b := NewSentenceBuilder(buf)
+	if err := b.Write("RMC", rmc.Marshal()); err != nil {
+		t.Fatalf("Error writing RMC: %v", err)
+	}
+	if err := b.Write("GGA", gga.Marshal()); err != nil {
+		t.Fatalf("Error writing GGA: %v", err)
+	}
+
+	exp := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.82,188.36,110706,3.1,W*52\r\n" +
+		"$GPGGA,162254.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*65\r\n"
+	if buf.String() != exp {
+		t.Errorf("Expected\n%q\ngot\n%q", exp, buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\r\n")
+	rh := &rmcHandler{}
+	if err := (&Processor{}).parseMessage(lines[0], rh); err != nil {
+		t.Fatalf("Error parsing built RMC: %v", err)
+	}
+	if !similar(t, rh.rmc, rmc) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", rh.rmc, rmc)
+	}
+	gh := &ggaHandler{}
+	if err := (&Processor{}).parseMessage(lines[1], gh); err != nil {
+		t.Fatalf("Error parsing built GGA: %v", err)
+	}
+	if !similar(t, gh.gga, gga) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", gh.gga, gga)
+	}
+}
+
+func TestSentenceBuilderInvalidFields(t *testing.T) {
+	b := NewSentenceBuilder(&bytes.Buffer{})
+	for _, f := range []string{"a,b", "a*b", "$", "\r\n"} {
+		if _, err := b.Build("TXT", []string{"ok", f}); err == nil {
+			t.Errorf("Expected error building with field %q", f)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package nmea
+
+import "math"
+
+// angleDiff returns the smallest difference between two angles in
+// degrees, accounting for wraparound at 360.
+func angleDiff(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
+
+// VelocityChecker cross-checks the speed and course reported by RMC
+// and VTG sentences within an epoch.
+//
+// A healthy receiver reports the same velocity in both; a mismatch
+// usually indicates a firmware or reception problem.  The checker
+// compares each RMC with the next VTG (or vice versa) and calls
+// Mismatch when they disagree beyond the configured tolerances.
+type VelocityChecker struct {
+	// SpeedTolerance is the allowed difference in knots.
+	SpeedTolerance float64
+	// AngleTolerance is the allowed difference in degrees.
+	AngleTolerance float64
+	// Mismatch is called with each disagreeing pair.
+	Mismatch func(RMC, VTG)
+
+	rmc *RMC
+	vtg *VTG
+}
+
+// HandleRMC satisfies RMCHandler.
+func (c *VelocityChecker) HandleRMC(r RMC) {
+	c.rmc = &r
+	c.check()
+}
+
+// HandleVTG satisfies VTGHandler.
+func (c *VelocityChecker) HandleVTG(v VTG) {
+	c.vtg = &v
+	c.check()
+}
+
+func (c *VelocityChecker) check() {
+	if c.rmc == nil || c.vtg == nil {
+		return
+	}
+	r, v := *c.rmc, *c.vtg
+	c.rmc, c.vtg = nil, nil
+
+	if math.Abs(r.Speed-v.Knots) > c.SpeedTolerance ||
+		angleDiff(r.Angle, v.True) > c.AngleTolerance {
+		if c.Mismatch != nil {
+			c.Mismatch(r, v)
+		}
+	}
+}
+
+// PositionChecker cross-checks the positions reported by GGA and
+// RMC sentences of the same epoch.
+//
+// Both should report the same fix, so a divergence suggests a
+// parsing problem or the streams of two receivers being interleaved.
+// Sentences are paired by their time of day; a sentence without a
+// counterpart from the same epoch is never compared.  Invalid fixes
+// are ignored.
+type PositionChecker struct {
+	// Tolerance is the allowed distance.  The default is one
+	// meter.
+	Tolerance Meters
+	// Mismatch is called with each disagreeing pair and the
+	// distance between their positions.
+	Mismatch func(gga GGA, rmc RMC, distance Meters)
+
+	gga *GGA
+	rmc *RMC
+}
+
+// HandleGGA satisfies GGAHandler.
+func (c *PositionChecker) HandleGGA(g GGA) {
+	if g.Valid() {
+		c.gga = &g
+		c.check()
+	}
+}
+
+// HandleRMC satisfies RMCHandler.
+func (c *PositionChecker) HandleRMC(r RMC) {
+	if r.Valid() {
+		c.rmc = &r
+		c.check()
+	}
+}
+
+func (c *PositionChecker) check() {
+	if c.gga == nil || c.rmc == nil || timeOfDay(c.gga.Taken) != timeOfDay(c.rmc.Timestamp) {
+		return
+	}
+	g, r := *c.gga, *c.rmc
+	c.gga, c.rmc = nil, nil
+
+	tolerance := c.Tolerance
+	if tolerance == 0 {
+		tolerance = 1
+	}
+	if d := g.Position().DistanceTo(r.Position()); d > tolerance && c.Mismatch != nil {
+		c.Mismatch(g, r, d)
+	}
+}
+
+// SatCountChecker cross-checks the number of satellites a GGA message
+// claims are in use against the satellites listed by the GSA messages
+// of the same epoch.
+//
+// Multi-constellation receivers emit one GSA per constellation, so
+// the satellites of every GSA following a GGA are summed.  The check
+// for an epoch happens when the next GGA arrives (or on Flush).
+type SatCountChecker struct {
+	// Tolerance is the allowed difference in satellite counts.
+	Tolerance int
+	// Mismatch is called with the GGA and the number of satellites
+	// used according to the GSA messages when they disagree.
+	Mismatch func(gga GGA, used int)
+
+	gga  *GGA
+	used int
+	gsas int
+}
+
+// HandleGGA satisfies GGAHandler.
+func (c *SatCountChecker) HandleGGA(g GGA) {
+	c.check()
+	c.gga = &g
+	c.used = 0
+	c.gsas = 0
+}
+
+// HandleGSA satisfies GSAHandler.
+func (c *SatCountChecker) HandleGSA(g GSA) {
+	c.used += len(g.SatsUsed)
+	c.gsas++
+}
+
+// Flush checks the last epoch.
+func (c *SatCountChecker) Flush() {
+	c.check()
+	c.gga = nil
+}
+
+func (c *SatCountChecker) check() {
+	if c.gga == nil || c.gsas == 0 {
+		return
+	}
+	d := c.gga.NumSats - c.used
+	if (d > c.Tolerance || -d > c.Tolerance) && c.Mismatch != nil {
+		c.Mismatch(*c.gga, c.used)
+	}
+}
@@ -0,0 +1,132 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAngleDiff(t *testing.T) {
+	tests := []struct {
+		a, b, exp float64
+	}{
+		{10, 20, 10},
+		{359, 1, 2},
+		{1, 359, 2},
+		{0, 180, 180},
+		{720, 10, 10},
+	}
+	for _, test := range tests {
+		if got := angleDiff(test.a, test.b); !near(got, test.exp) {
+			t.Errorf("angleDiff(%v, %v) = %v, want %v", test.a, test.b, got, test.exp)
+		}
+	}
+}
+
+func TestVelocityCheckerSample(t *testing.T) {
+	mismatches := 0
+	c := &VelocityChecker{
+		SpeedTolerance: 0.1,
+		AngleTolerance: 1,
+		Mismatch:       func(RMC, VTG) { mismatches++ },
+	}
+	if err := Process(strings.NewReader(ubloxSample), c, nil); err != nil {
+		t.Fatalf("Error processing sample: %v", err)
+	}
+	if mismatches != 0 {
+		t.Errorf("Expected no mismatches in the ublox sample, got %v", mismatches)
+	}
+}
+
+func TestVelocityCheckerMismatch(t *testing.T) {
+	var got []VTG
+	c := &VelocityChecker{
+		SpeedTolerance: 0.1,
+		AngleTolerance: 1,
+		Mismatch:       func(r RMC, v VTG) { got = append(got, v) },
+	}
+
+	c.HandleRMC(RMC{Speed: 5.5, Angle: 359.5})
+	c.HandleVTG(VTG{True: 0.2, Knots: 5.52})
+	c.HandleRMC(RMC{Speed: 5.5, Angle: 54.7})
+	c.HandleVTG(VTG{True: 34.4, Knots: 5.5})
+	c.HandleVTG(VTG{True: 54.7, Knots: 9.1})
+	c.HandleRMC(RMC{Speed: 5.5, Angle: 54.7})
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 mismatches, got %v", got)
+	}
+	if got[0].True != 34.4 || got[1].Knots != 9.1 {
+		t.Errorf("Wrong mismatches reported: %v", got)
+	}
+}
+
+func TestSatCountChecker(t *testing.T) {
+	type mismatch struct {
+		numSats, used int
+	}
+	var got []mismatch
+	c := &SatCountChecker{
+		Mismatch: func(g GGA, used int) { got = append(got, mismatch{g.NumSats, used}) },
+	}
+
+	if err := Process(strings.NewReader(ubloxSample), c, nil); err != nil {
+		t.Fatalf("Error processing sample: %v", err)
+	}
+	c.Flush()
+	if len(got) != 0 {
+		t.Errorf("Expected no mismatches in the ublox sample, got %v", got)
+	}
+
+	// Matching multi-constellation epoch.
+	c.HandleGGA(GGA{NumSats: 5})
+	c.HandleGSA(GSA{SatsUsed: []int{1, 2, 3}})
+	c.HandleGSA(GSA{SatsUsed: []int{65, 66}})
+	// Mismatched epoch.
+	c.HandleGGA(GGA{NumSats: 8})
+	c.HandleGSA(GSA{SatsUsed: []int{1, 2, 3}})
+	c.HandleGSA(GSA{SatsUsed: []int{65, 66}})
+	// Epoch without GSA isn't checked.
+	c.HandleGGA(GGA{NumSats: 8})
+	c.HandleGGA(GGA{NumSats: 4})
+	c.HandleGSA(GSA{SatsUsed: []int{1, 2}})
+	c.Flush()
+
+	exp := []mismatch{{8, 5}, {4, 2}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected mismatches %v, got %v", exp, got)
+	}
+
+	got = nil
+	c.Tolerance = 2
+	c.HandleGGA(GGA{NumSats: 4})
+	c.HandleGSA(GSA{SatsUsed: []int{1, 2}})
+	c.Flush()
+	if len(got) != 0 {
+		t.Errorf("Expected mismatch within tolerance to be ignored, got %v", got)
+	}
+}
+
+func TestPositionChecker(t *testing.T) {
+	var distances []Meters
+	c := &PositionChecker{Mismatch: func(g GGA, r RMC, d Meters) { distances = append(distances, d) }}
+	if err := Process(strings.NewReader(ubloxSample), c, nil); err != nil {
+		t.Fatalf("Error processing sample: %v", err)
+	}
+	if len(distances) != 0 {
+		t.Errorf("Expected no mismatches in the ublox sample, got %v", distances)
+	}
+
+	// Only the 162255 epoch has both sentences, a hundredth of a
+	// minute (about 18.5m) apart.
+	const input = "$GPGGA,162254.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*65\n" +
+		"$GPGGA,162255.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*64\n" +
+		"$GPRMC,162255.00,A,3723.03837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" +
+		"$GPGGA,162256.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*67\n"
+	if err := Process(strings.NewReader(input), c, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(distances) != 1 || distances[0] < 18 || distances[0] > 19 {
+		t.Errorf("Expected a single mismatch of about 18.5m, got %v", distances)
+	}
+}
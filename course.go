@@ -0,0 +1,68 @@
+package nmea
+
+import "math"
+
+// CourseFilter smooths the course over ground reported by RMC or VTG
+// messages, for example to keep a map's orientation steady.
+//
+// Courses are averaged as unit vectors over a moving window, so they
+// wrap correctly across north.  Courses reported below MinSpeed,
+// where they're mostly noise, are ignored, as are void fixes.  Most
+// receivers report the same course in both RMC and VTG, so it's
+// usually best to feed only one of them.
+type CourseFilter struct {
+	// Window is the number of courses averaged.  Zero means 5.
+	Window int
+	// MinSpeed is the speed in knots below which courses are
+	// ignored.
+	MinSpeed float64
+	// Course is called with the smoothed course, in degrees
+	// ([0,360)), after each course accepted.
+	Course func(course float64)
+
+	sin, cos []float64
+	next     int
+}
+
+// HandleRMC satisfies RMCHandler.
+func (c *CourseFilter) HandleRMC(r RMC) {
+	if r.Valid() {
+		c.add(r.Angle, r.Speed)
+	}
+}
+
+// HandleVTG satisfies VTGHandler.
+func (c *CourseFilter) HandleVTG(v VTG) {
+	if v.Valid {
+		c.add(v.True, v.Knots)
+	}
+}
+
+func (c *CourseFilter) add(course, speed float64) {
+	if speed < c.MinSpeed {
+		return
+	}
+	window := c.Window
+	if window == 0 {
+		window = 5
+	}
+
+	s, co := math.Sincos(d2r(course))
+	if len(c.sin) < window {
+		c.sin = append(c.sin, s)
+		c.cos = append(c.cos, co)
+	} else {
+		c.sin[c.next] = s
+		c.cos[c.next] = co
+		c.next = (c.next + 1) % window
+	}
+
+	var sumSin, sumCos float64
+	for i := range c.sin {
+		sumSin += c.sin[i]
+		sumCos += c.cos[i]
+	}
+	if c.Course != nil {
+		c.Course(math.Mod(r2d(math.Atan2(sumSin, sumCos))+360, 360))
+	}
+}
@@ -0,0 +1,39 @@
+package nmea
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCourseFilter(t *testing.T) {
+	var got []float64
+	c := &CourseFilter{
+		Window:   4,
+		MinSpeed: 1,
+		Course:   func(course float64) { got = append(got, course) },
+	}
+
+	for _, v := range []VTG{
+		{True: 350, Knots: 5, Valid: true},
+		{True: 10, Knots: 5, Valid: true},
+		{True: 180, Knots: 0.2, Valid: true}, // too slow, ignored
+		{True: 356, Knots: 5, Valid: true},
+		{True: 4, Knots: 5, Valid: true},
+		{True: 20, Knots: 5, Valid: true}, // pushes out the 350
+		{True: 180, Knots: 5},             // no velocity solution, ignored
+	} {
+		c.HandleVTG(v)
+	}
+	c.HandleRMC(RMC{Angle: 180, Speed: 5, Status: 'V'})
+
+	exp := []float64{350, 0, 358.653261, 0, 7.494819}
+	if len(got) != len(exp) {
+		t.Fatalf("Expected %v courses, got %v", exp, got)
+	}
+	for i := range exp {
+		// Anything within ε of north may come out either side of it.
+		if d := math.Abs(angleDiff(got[i], exp[i])); d > 0.0001 {
+			t.Errorf("Course %v: expected %v, got %v", i, exp[i], got[i])
+		}
+	}
+}
@@ -2,4 +2,10 @@
 // In its most simple usage, you define a type that satisfies the
 // "Handler" types for the various types of messages you wish to receive,
 // and pass it to Process.
+//
+// Fields following those a sentence type defines, such as those added
+// by later NMEA versions or vendor extensions, are kept only for the
+// fix messages RMC, GGA, GLL and VTG, in their ExtraFields.  Other
+// types ignore them; a parser registered with RegisterParser can
+// recover them where needed.
 package nmea
@@ -0,0 +1,49 @@
+package nmea
+
+// DuplicateDetector watches for sentences that repeat verbatim.
+//
+// Some receivers repeat the same sentence across epochs when their
+// fix goes stale, so a byte-identical repeat of the previous sentence
+// of the same type usually indicates a hung receiver.  That only
+// holds for sentences carrying a time, since the others, such as GSA
+// and VTG, legitimately repeat while the receiver stands still, so
+// by default only RMC, GGA, GLL and ZDA sentences are watched.
+//
+// Being a RawHandler, DuplicateDetector needs no parsed sentences;
+// embed it in the application's handler to watch its stream.
+type DuplicateDetector struct {
+	// Duplicate is called with each sentence identical to the
+	// previous sentence of its type.
+	Duplicate func(line string)
+	// Types lists the sentence types watched, e.g. "RMC".  Nil
+	// means the types with a timestamp.
+	Types []string
+
+	last map[string]string
+}
+
+// duplicateTypes are the types DuplicateDetector watches by default.
+var duplicateTypes = []string{"RMC", "GGA", "GLL", "ZDA"}
+
+// HandleRaw satisfies RawHandler.
+func (d *DuplicateDetector) HandleRaw(line string) {
+	types := d.Types
+	if types == nil {
+		types = duplicateTypes
+	}
+	typ := sentenceType(line)
+	watched := false
+	for _, t := range types {
+		watched = watched || t == typ
+	}
+	if !watched {
+		return
+	}
+	if d.last == nil {
+		d.last = map[string]string{}
+	}
+	if d.last[typ] == line && d.Duplicate != nil {
+		d.Duplicate(line)
+	}
+	d.last[typ] = line
+}
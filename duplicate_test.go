@@ -0,0 +1,43 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateDetector(t *testing.T) {
+	rmc := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74"
+	input := strings.Join([]string{
+		rmc,
+		"$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F",
+		rmc,
+		"$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F",
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72",
+		"$GPRMC,054705.559,V,1746.690,N,15219.254,W,78.1,39.99,150315,,E*78",
+	}, "\n")
+
+	var got []string
+	d := &DuplicateDetector{Duplicate: func(line string) { got = append(got, line) }}
+	if err := Process(strings.NewReader(input), d, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := []string{rmc}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected duplicates %q, got %q", exp, got)
+	}
+
+	got = nil
+	d = &DuplicateDetector{
+		Duplicate: func(line string) { got = append(got, line) },
+		Types:     []string{"VTG"},
+	}
+	if err := Process(strings.NewReader(input), d, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	exp = []string{"$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F"}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected duplicates %q, got %q", exp, got)
+	}
+}
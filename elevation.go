@@ -0,0 +1,67 @@
+package nmea
+
+// ElevationProfile accumulates total ascent and descent from the
+// altitudes reported in a stream of GGA messages.
+//
+// Altitude changes smaller than Threshold meters from the last
+// counted altitude are treated as noise.  Small changes still add up:
+// once the altitude has drifted Threshold meters from the last
+// counted value, the whole change is counted.  Fixes with an invalid
+// quality are ignored.
+type ElevationProfile struct {
+	// Threshold is the minimum change in meters that is counted.
+	Threshold float64
+
+	n          int
+	ref        float64
+	gain, loss float64
+	min, max   float64
+}
+
+// HandleGGA satisfies GGAHandler.
+func (e *ElevationProfile) HandleGGA(g GGA) {
+	if !g.Valid() {
+		return
+	}
+	alt := g.Altitude
+	if e.n == 0 {
+		e.ref, e.min, e.max = alt, alt, alt
+	}
+	e.n++
+
+	if alt < e.min {
+		e.min = alt
+	}
+	if alt > e.max {
+		e.max = alt
+	}
+
+	switch d := alt - e.ref; {
+	case d >= e.Threshold && d > 0:
+		e.gain += d
+		e.ref = alt
+	case -d >= e.Threshold && d < 0:
+		e.loss -= d
+		e.ref = alt
+	}
+}
+
+// Gain returns the total ascent in meters.
+func (e *ElevationProfile) Gain() float64 {
+	return e.gain
+}
+
+// Loss returns the total descent in meters as a positive number.
+func (e *ElevationProfile) Loss() float64 {
+	return e.loss
+}
+
+// Min returns the lowest altitude seen.
+func (e *ElevationProfile) Min() float64 {
+	return e.min
+}
+
+// Max returns the highest altitude seen.
+func (e *ElevationProfile) Max() float64 {
+	return e.max
+}
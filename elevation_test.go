@@ -0,0 +1,37 @@
+package nmea
+
+import "testing"
+
+func TestElevationProfile(t *testing.T) {
+	alts := []float64{
+		100, 101, 99, 102, // noise
+		105, 110, 120, 119, 121, // climb to 120ish
+		118, 110, 100, 90, // descend to 90
+		91, 89, 90, // noise
+		-5, // below sea level
+	}
+
+	tests := []struct {
+		threshold  float64
+		gain, loss float64
+	}{
+		{0, 26, 131},
+		{3, 20, 125},
+		{25, 0, 105},
+	}
+
+	for _, test := range tests {
+		e := &ElevationProfile{Threshold: test.threshold}
+		e.HandleGGA(GGA{Quality: InvalidFix, Altitude: 5000})
+		for _, a := range alts {
+			e.HandleGGA(GGA{Quality: GPSFix, Altitude: a})
+		}
+		if !near(e.Gain(), test.gain) || !near(e.Loss(), test.loss) {
+			t.Errorf("With threshold %v expected gain/loss %v/%v, got %v/%v",
+				test.threshold, test.gain, test.loss, e.Gain(), e.Loss())
+		}
+		if e.Min() != -5 || e.Max() != 121 {
+			t.Errorf("Expected min/max -5/121, got %v/%v", e.Min(), e.Max())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package nmea
+
+import (
+	"fmt"
+	"io"
+)
+
+// An Emitter handles every message type that can be marshaled back
+// into a sentence, such as those of a SentenceWriter.
+type Emitter interface {
+	GGAHandler
+	GLLHandler
+	GSAHandler
+	GSVHandler
+	RMCHandler
+	VTGHandler
+	ZDAHandler
+}
+
+// SentenceWriter is an Emitter writing each message it handles as a
+// sentence with a CRLF terminator, e.g. to pass messages received
+// by other means on to a device expecting NMEA.
+//
+// Messages are written with their Marshal methods, so reparsing a
+// written sentence reproduces the message, apart from values beyond
+// the precision of its fields.
+type SentenceWriter struct {
+	// Builder builds and writes the sentences, and sets their
+	// talker ID.
+	Builder *SentenceBuilder
+
+	err error
+}
+
+// NewSentenceWriter returns a SentenceWriter writing GP sentences to
+// w.
+func NewSentenceWriter(w io.Writer) *SentenceWriter {
+	return &SentenceWriter{Builder: NewSentenceBuilder(w)}
+}
+
+// Emit writes msg, which must be one of the message types an Emitter
+// handles, returning any error.
+func (s *SentenceWriter) Emit(msg interface{}) error {
+	var typ string
+	var fields []string
+	switch m := msg.(type) {
+	case GGA:
+		typ, fields = "GGA", m.Marshal()
+	case GLL:
+		typ, fields = "GLL", m.Marshal()
+	case GSA:
+		typ, fields = "GSA", m.Marshal()
+	case GSV:
+		typ, fields = "GSV", m.Marshal()
+	case RMC:
+		typ, fields = "RMC", m.Marshal()
+	case VTG:
+		typ, fields = "VTG", m.Marshal()
+	case ZDA:
+		typ, fields = "ZDA", m.Marshal()
+	default:
+		return fmt.Errorf("can't emit %T", msg)
+	}
+	return s.Builder.Write(typ, fields)
+}
+
+// emit writes msg, remembering the first error.
+func (s *SentenceWriter) emit(msg interface{}) {
+	if s.err == nil {
+		s.err = s.Emit(msg)
+	}
+}
+
+// Err returns the first error writing a message handled, if any.
+// Nothing more is written after an error.
+func (s *SentenceWriter) Err() error {
+	return s.err
+}
+
+// HandleGGA satisfies GGAHandler.
+func (s *SentenceWriter) HandleGGA(m GGA) { s.emit(m) }
+
+// HandleGLL satisfies GLLHandler.
+func (s *SentenceWriter) HandleGLL(m GLL) { s.emit(m) }
+
+// HandleGSA satisfies GSAHandler.
+func (s *SentenceWriter) HandleGSA(m GSA) { s.emit(m) }
+
+// HandleGSV satisfies GSVHandler.
+func (s *SentenceWriter) HandleGSV(m GSV) { s.emit(m) }
+
+// HandleRMC satisfies RMCHandler.
+func (s *SentenceWriter) HandleRMC(m RMC) { s.emit(m) }
+
+// HandleVTG satisfies VTGHandler.
+func (s *SentenceWriter) HandleVTG(m VTG) { s.emit(m) }
+
+// HandleZDA satisfies ZDAHandler.
+func (s *SentenceWriter) HandleZDA(m ZDA) { s.emit(m) }
@@ -0,0 +1,69 @@
+package nmea
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var _ Emitter = &SentenceWriter{}
+
+func TestSentenceWriterRoundTrip(t *testing.T) {
+	buf := &strings.Builder{}
+	w := NewSentenceWriter(buf)
+	if err := Process(strings.NewReader(ubloxSample), w, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if w.Err() != nil {
+		t.Fatalf("Error writing: %v", w.Err())
+	}
+
+	var want []interface{}
+	for _, line := range strings.Split(strings.TrimSpace(ubloxSample), "\n") {
+		m, err := ParseLine(line)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %v", line, err)
+		}
+		want = append(want, m)
+	}
+
+	s := bufio.NewScanner(strings.NewReader(buf.String()))
+	for i := 0; s.Scan(); i++ {
+		line := s.Text()
+		if !Valid(line) {
+			t.Errorf("Invalid sentence emitted: %q", line)
+			continue
+		}
+		got, err := ParseLine(line)
+		if err != nil {
+			t.Errorf("Error reparsing %q: %v", line, err)
+			continue
+		}
+		if i >= len(want) || !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("Sentence %v (%q) doesn't round trip:\n%#v", i, line, got)
+		}
+	}
+	if !strings.HasSuffix(buf.String(), "\r\n") || strings.Count(buf.String(), "\r\n") != len(want) {
+		t.Errorf("Expected %v CRLF terminated sentences, got %q", len(want), buf)
+	}
+}
+
+func TestSentenceWriterExtraFields(t *testing.T) {
+	const in = "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A,V*0E"
+	m, err := ParseLine(in)
+	if err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	buf := &strings.Builder{}
+	if err := NewSentenceWriter(buf).Emit(m); err != nil {
+		t.Fatalf("Error emitting: %v", err)
+	}
+	if got, err := ParseLine(strings.TrimSpace(buf.String())); err != nil || !reflect.DeepEqual(got, m) {
+		t.Errorf("Expected %#v, got %#v (%v)", m, got, err)
+	}
+
+	if err := NewSentenceWriter(buf).Emit(PGRME{}); err == nil {
+		t.Errorf("Expected an error emitting a PGRME")
+	}
+}
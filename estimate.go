@@ -0,0 +1,77 @@
+package nmea
+
+import "time"
+
+// SpeedEstimator derives the speed and course over ground from
+// consecutive GGA or GLL fixes, for receivers reporting neither RMC
+// nor VTG.
+//
+// Each fix after the first yields a synthetic VTG with the speed and
+// true course from the previous fix, an Estimated mode, and no
+// magnetic course.  Fixes without a timestamp or reported as invalid
+// are ignored.  A fix no later than the previous one (by time of day,
+// a step back or of more than 12 hours) restarts the estimate rather
+// than yielding a nonsensical speed.  Between identical positions the
+// speed is zero and the previous course is kept, since no direction
+// can be derived.
+//
+// Feeding both GGA and GLL for the same fixes is harmless, as the
+// second of each pair is ignored.
+type SpeedEstimator struct {
+	// VTG is called with each estimate.
+	VTG func(VTG)
+
+	last     time.Time
+	lat, lon float64
+	course   float64
+	started  bool
+}
+
+// HandleGGA satisfies GGAHandler.
+func (s *SpeedEstimator) HandleGGA(g GGA) {
+	if g.Valid() {
+		s.add(g.Taken, g.Latitude, g.Longitude)
+	}
+}
+
+// HandleGLL satisfies GLLHandler.
+func (s *SpeedEstimator) HandleGLL(g GLL) {
+	if g.Valid() {
+		s.add(g.Taken, g.Latitude, g.Longitude)
+	}
+}
+
+func (s *SpeedEstimator) add(t time.Time, lat, lon float64) {
+	if t.IsZero() {
+		return
+	}
+	if !s.started {
+		s.started = true
+		s.last, s.lat, s.lon = t, lat, lon
+		return
+	}
+	dt := timeBetween(s.last, t)
+	if dt == 0 {
+		return
+	}
+	prevLat, prevLon := s.lat, s.lon
+	s.last, s.lat, s.lon = t, lat, lon
+	if dt < 0 || dt > 12*time.Hour {
+		return
+	}
+
+	dist := float64(Distance(prevLat, prevLon, lat, lon))
+	if dist > 0 {
+		s.course = Bearing(prevLat, prevLon, lat, lon)
+	}
+	knots := dist / metersPerKnot / dt.Seconds()
+	if s.VTG != nil {
+		s.VTG(VTG{
+			True:  s.course,
+			Knots: knots,
+			KMH:   knots * kmhPerKnot,
+			Mode:  Estimated,
+			Valid: true,
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package nmea
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpeedEstimator(t *testing.T) {
+	b := NewSentenceBuilder(nil)
+	t0 := time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC)
+	fixes := []struct {
+		dt  time.Duration
+		lat float64
+	}{
+		{0, 37},
+		{time.Minute, 37 + 1.0/60}, // a nautical mile north
+		{time.Minute, 37 + 1.0/60}, // repeated
+		{30 * time.Second, 37},     // back in time
+		{2 * time.Minute, 37},      // stationary
+	}
+	var lines []string
+	for _, f := range fixes {
+		gga, err := b.Build("GGA", GGA{Taken: t0.Add(f.dt), Latitude: f.lat, Longitude: -122, Quality: GPSFix}.Marshal())
+		if err != nil {
+			t.Fatalf("Error building GGA: %v", err)
+		}
+		lines = append(lines, gga)
+	}
+
+	var got []VTG
+	s := &SpeedEstimator{VTG: func(v VTG) { got = append(got, v) }}
+	if err := Process(strings.NewReader(strings.Join(lines, "")), s, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 estimates, got %+v", got)
+	}
+	if v := got[0]; math.Abs(v.Knots-60) > 0.1 || math.Abs(v.KMH-60*1.852) > 0.2 || math.Abs(v.True) > 0.01 || !v.Valid {
+		t.Errorf("Expected 60 knots due north, got %+v", v)
+	}
+	if v := got[1]; v.Knots != 0 {
+		t.Errorf("Expected a stationary estimate, got %+v", v)
+	}
+}
@@ -0,0 +1,55 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	m, err := ParseLine("$GPZDA,162254.00,11,07,2006,00,00*63")
+	if err != nil {
+		t.Fatalf("Error parsing ZDA: %v", err)
+	}
+	if _, ok := m.(ZDA); !ok {
+		t.Errorf("Expected a ZDA, got %#v", m)
+	}
+
+	if _, err := ParseLine("$GPXXX,1,2,3*53"); err != ErrUnhandled {
+		t.Errorf("Expected ErrUnhandled, got %v", err)
+	}
+	if _, err := ParseLine("$GPZDA,162254.00,11,07,2006,00,00*64"); err == nil {
+		t.Errorf("Expected a checksum error")
+	}
+}
+
+func TestParseLinePanics(t *testing.T) {
+	// Inputs that used to panic.
+	tests := []string{
+		"$GPRMC,054841.9,V,3,E,,N,052.80.85864.2,38.21,150315,,E*66",
+		"$GPRMC,123519,,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*2B",
+		"$*00",
+	}
+	for _, test := range tests {
+		if !checkChecksum(test) {
+			t.Errorf("Bad checksum in test input %q", test)
+		}
+		ParseLine(test)
+	}
+}
+
+func FuzzParseLine(f *testing.F) {
+	for _, s := range strings.Split(ubloxSample+freeNmeaSample, "\n") {
+		f.Add(s)
+	}
+	f.Add("$PUBX,00,081350.00,4717.113210,N,00833.915187,E,546.589,G3,2.1,2.0,0.007,77.52,0.007,,0.92,1.19,0.77,9,0,0*5F")
+	f.Add("$PGRME,15.0,M,45.0,M,25.0,M*1C")
+	f.Add("$GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73")
+	f.Add("$GPWPL,4917.16,N,12310.64,W,003*65")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		m, err := ParseLine(line)
+		if err == nil && m == nil {
+			t.Errorf("No message and no error parsing %q", line)
+		}
+	})
+}
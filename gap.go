@@ -0,0 +1,65 @@
+package nmea
+
+import "time"
+
+// GapDetector reports discontinuities in a stream of fixes, such as
+// when a receiver briefly loses power or signal.
+//
+// It tracks the time of the last valid RMC or GGA, and reports when
+// the next valid fix is more than Threshold later.  Since GGA carries
+// no date, fixes are compared by time of day whenever either lacks
+// one, assuming gaps shorter than a day across midnight.
+type GapDetector struct {
+	// Threshold is the longest interval between consecutive fixes
+	// that isn't reported.
+	Threshold time.Duration
+	// Gap is called with the times of the fixes on either side of
+	// each gap, and the length of the gap.
+	Gap func(last, next time.Time, gap time.Duration)
+
+	last time.Time
+}
+
+// HandleRMC satisfies RMCHandler.
+func (g *GapDetector) HandleRMC(r RMC) {
+	if r.Valid() {
+		g.fix(r.Timestamp)
+	}
+}
+
+// HandleGGA satisfies GGAHandler.
+func (g *GapDetector) HandleGGA(f GGA) {
+	if f.Valid() {
+		g.fix(f.Taken)
+	}
+}
+
+func (g *GapDetector) fix(t time.Time) {
+	if !g.last.IsZero() {
+		if d := timeBetween(g.last, t); d > g.Threshold && g.Gap != nil {
+			g.Gap(g.last, t, d)
+		}
+	}
+	g.last = t
+}
+
+// timeBetween returns the time from a to b.  If either lacks a date,
+// only their times of day are compared, and b is assumed to follow a
+// within a day.
+func timeBetween(a, b time.Time) time.Duration {
+	if a.Year() != 0 && b.Year() != 0 {
+		return b.Sub(a)
+	}
+	d := timeOfDay(b) - timeOfDay(a)
+	if d < 0 {
+		d += 24 * time.Hour
+	}
+	return d
+}
+
+// timeOfDay returns the time since midnight.
+func timeOfDay(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+}
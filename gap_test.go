@@ -0,0 +1,43 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGapDetector(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		exp   []time.Duration
+	}{
+		{"rmc", "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" +
+			"$GPRMC,162324.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72\n" +
+			"$GPRMC,162325.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*73\n",
+			[]time.Duration{30 * time.Second}},
+		{"gga across midnight", "$GPGGA,235950.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*6B\n" +
+			"$GPGGA,235951.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*6A\n" +
+			"$GPGGA,000021.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*60\n" +
+			"$GPGGA,000022.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*63\n",
+			[]time.Duration{30 * time.Second}},
+		{"mixed", ubloxSample, nil},
+	}
+
+	for _, test := range tests {
+		var got []time.Duration
+		h := &GapDetector{
+			Threshold: 5 * time.Second,
+			Gap: func(last, next time.Time, gap time.Duration) {
+				got = append(got, gap)
+			},
+		}
+		if err := Process(strings.NewReader(test.input), h, nil); err != nil {
+			t.Fatalf("%v: error processing: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("%v: expected gaps %v, got %v", test.name, test.exp, got)
+		}
+	}
+}
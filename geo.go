@@ -0,0 +1,131 @@
+package nmea
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadius is the mean radius of the earth in meters.
+const earthRadius = 6371000
+
+// metersPerKnot is the distance in meters covered in one second at
+// one knot.
+const metersPerKnot = 1852.0 / 3600.0
+
+// kmhPerKnot is one knot in kilometers per hour.
+const kmhPerKnot = 1.852
+
+// Meters is a distance in meters.
+type Meters float64
+
+// ToFeet returns the distance in international feet.
+func (m Meters) ToFeet() float64 {
+	return float64(m) / 0.3048
+}
+
+// ToNauticalMiles returns the distance in nautical miles.
+func (m Meters) ToNauticalMiles() float64 {
+	return float64(m) / 1852
+}
+
+// ToKilometers returns the distance in kilometers.
+func (m Meters) ToKilometers() float64 {
+	return float64(m) / 1000
+}
+
+func d2r(d float64) float64 {
+	return d * math.Pi / 180.0
+}
+
+func r2d(r float64) float64 {
+	return r * 180.0 / math.Pi
+}
+
+// Distance returns the great-circle distance between two points.
+func Distance(lat1, lon1, lat2, lon2 float64) Meters {
+	φ1 := d2r(lat1)
+	φ2 := d2r(lat2)
+	Δφ := d2r(lat2 - lat1)
+	Δλ := d2r(lon2 - lon1)
+
+	a := math.Sin(Δφ/2)*math.Sin(Δφ/2) +
+		math.Cos(φ1)*math.Cos(φ2)*
+			math.Sin(Δλ/2)*math.Sin(Δλ/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return Meters(earthRadius * c)
+}
+
+// Bearing returns the initial great-circle bearing in degrees
+// ([0,360)) from the first point to the second.
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	φ1 := d2r(lat1)
+	φ2 := d2r(lat2)
+	Δλ := d2r(lon2 - lon1)
+
+	y := math.Sin(Δλ) * math.Cos(φ2)
+	x := math.Cos(φ1)*math.Sin(φ2) - math.Sin(φ1)*math.Cos(φ2)*math.Cos(Δλ)
+
+	return math.Mod(r2d(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached by travelling the given
+// distance along a great circle from the given point with the given
+// initial bearing in degrees.
+func Destination(lat, lon, bearing float64, dist Meters) (float64, float64) {
+	φ1 := d2r(lat)
+	λ1 := d2r(lon)
+	θ := d2r(bearing)
+	δ := float64(dist) / earthRadius
+
+	φ2 := math.Asin(math.Sin(φ1)*math.Cos(δ) + math.Cos(φ1)*math.Sin(δ)*math.Cos(θ))
+	λ2 := λ1 + math.Atan2(math.Sin(θ)*math.Sin(δ)*math.Cos(φ1),
+		math.Cos(δ)-math.Sin(φ1)*math.Sin(φ2))
+
+	return r2d(φ2), normalizeLon(r2d(λ2))
+}
+
+// intermediate returns the point at fraction f of the way along the
+// great circle between two points.
+func intermediate(lat1, lon1, lat2, lon2, f float64) (float64, float64) {
+	φ1, λ1 := d2r(lat1), d2r(lon1)
+	φ2, λ2 := d2r(lat2), d2r(lon2)
+
+	δ := float64(Distance(lat1, lon1, lat2, lon2)) / earthRadius
+	if δ == 0 {
+		return lat1, lon1
+	}
+
+	a := math.Sin((1-f)*δ) / math.Sin(δ)
+	b := math.Sin(f*δ) / math.Sin(δ)
+	x := a*math.Cos(φ1)*math.Cos(λ1) + b*math.Cos(φ2)*math.Cos(λ2)
+	y := a*math.Cos(φ1)*math.Sin(λ1) + b*math.Cos(φ2)*math.Sin(λ2)
+	z := a*math.Sin(φ1) + b*math.Sin(φ2)
+
+	return r2d(math.Atan2(z, math.Sqrt(x*x+y*y))), r2d(math.Atan2(y, x))
+}
+
+// normalizeLon maps a longitude into [-180,180).
+func normalizeLon(lon float64) float64 {
+	return math.Mod(math.Mod(lon+180, 360)+360, 360) - 180
+}
+
+// Interpolate estimates the position at time t given the fixes a and
+// b, where a precedes b.
+//
+// Between a and b the position is interpolated along the great circle
+// in proportion to elapsed time.  Beyond b the position is dead
+// reckoned from b's speed and course.  Times before a (or a b that
+// doesn't follow a) yield a's position.
+func Interpolate(a, b RMC, t time.Time) (lat, lon float64) {
+	span := b.Timestamp.Sub(a.Timestamp)
+	switch {
+	case span <= 0 || !t.After(a.Timestamp):
+		return a.Latitude, a.Longitude
+	case t.After(b.Timestamp):
+		dist := Meters(b.Speed * metersPerKnot * t.Sub(b.Timestamp).Seconds())
+		return Destination(b.Latitude, b.Longitude, b.Angle, dist)
+	}
+	f := float64(t.Sub(a.Timestamp)) / float64(span)
+	return intermediate(a.Latitude, a.Longitude, b.Latitude, b.Longitude, f)
+}
@@ -0,0 +1,106 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		lat1, lon1, lat2, lon2 float64
+		exp                    Meters
+	}{
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 1, 111194.92664455873},
+		{0, 179.5, 0, -179.5, 111194.92664455873},
+		{37.383806, -121.989975, 37.774929, -122.419416, 57650.50396},
+	}
+	for _, test := range tests {
+		got := Distance(test.lat1, test.lon1, test.lat2, test.lon2)
+		if got-test.exp > 0.01 || test.exp-got > 0.01 {
+			t.Errorf("Distance(%v) = %v, want %v", test, got, test.exp)
+		}
+	}
+}
+
+func TestMetersConversions(t *testing.T) {
+	m := Meters(1852)
+	if got := m.ToNauticalMiles(); !near(got, 1) {
+		t.Errorf("Expected 1nm, got %v", got)
+	}
+	if got := m.ToKilometers(); !near(got, 1.852) {
+		t.Errorf("Expected 1.852km, got %v", got)
+	}
+	if got := m.ToFeet(); !near(got, 6076.115486) {
+		t.Errorf("Expected 6076.115486ft, got %v", got)
+	}
+	if got := Meters(0.3048).ToFeet(); !near(got, 1) {
+		t.Errorf("Expected 1ft, got %v", got)
+	}
+
+	// A minute of latitude is roughly a nautical mile.
+	if got := Distance(0, 0, 1.0/60, 0).ToNauticalMiles(); got < 0.99 || got > 1.01 {
+		t.Errorf("Expected a minute of latitude to be about 1nm, got %v", got)
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		lat1, lon1, lat2, lon2 float64
+		exp                    float64
+	}{
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 1, 90},
+		{1, 0, 0, 0, 180},
+		{0, 1, 0, 0, 270},
+		{0, 179.5, 0, -179.5, 90},
+	}
+	for _, test := range tests {
+		if got := Bearing(test.lat1, test.lon1, test.lat2, test.lon2); !near(got, test.exp) {
+			t.Errorf("Bearing(%v) = %v, want %v", test, got, test.exp)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	lat, lon := Destination(37.383806, -121.989975, 315, 57650.50396)
+	back := Distance(lat, lon, 37.383806, -121.989975)
+	if back-57650.50396 > 0.01 || 57650.50396-back > 0.01 {
+		t.Errorf("Expected to end up 57650.50396m away, got %v (%v, %v)", back, lat, lon)
+	}
+
+	lat, lon = Destination(0, 179.5, 90, 111194.92664455873)
+	if !near(lat, 0) || !near(lon, -179.5) {
+		t.Errorf("Expected to cross the antimeridian to -179.5, got %v, %v", lat, lon)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	t0 := time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC)
+	a := RMC{Timestamp: t0, Latitude: 0, Longitude: 0}
+	b := RMC{Timestamp: t0.Add(10 * time.Second), Latitude: 0, Longitude: 10, Speed: 60, Angle: 90}
+
+	tests := []struct {
+		name     string
+		a, b     RMC
+		at       time.Time
+		lat, lon float64
+	}{
+		{"start", a, b, t0, 0, 0},
+		{"before", a, b, t0.Add(-time.Hour), 0, 0},
+		{"midpoint", a, b, t0.Add(5 * time.Second), 0, 5},
+		{"quarter", a, b, t0.Add(2500 * time.Millisecond), 0, 2.5},
+		{"end", a, b, t0.Add(10 * time.Second), 0, 10},
+		// 60 knots for an hour is 111120m, along the equator.
+		{"beyond", a, b, t0.Add(10*time.Second + time.Hour), 0, 10 + r2d(111120.0/earthRadius)},
+		{"meridian", a, RMC{Timestamp: t0.Add(time.Minute), Latitude: 10}, t0.Add(30 * time.Second), 5, 0},
+		{"backwards", b, a, t0.Add(5 * time.Second), 0, 10},
+	}
+
+	for _, test := range tests {
+		lat, lon := Interpolate(test.a, test.b, test.at)
+		if !near(lat, test.lat) || !near(lon, test.lon) {
+			t.Errorf("%v: expected %v,%v, got %v,%v", test.name, test.lat, test.lon, lat, lon)
+		}
+	}
+}
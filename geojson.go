@@ -0,0 +1,54 @@
+package nmea
+
+import "encoding/json"
+
+// geoJSONFeature is a GeoJSON (RFC 7946) Feature with a Point
+// geometry.
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type string `json:"type"`
+	// Coordinates are longitude, latitude and, optionally,
+	// altitude, in that order.
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func geoJSON(coords []float64, props map[string]interface{}) ([]byte, error) {
+	return json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		Geometry:   geoJSONPoint{Type: "Point", Coordinates: coords},
+		Properties: props,
+	})
+}
+
+// GeoJSON returns the fix as a GeoJSON Feature with a Point geometry.
+// Its properties are the timestamp, speed (knots), course, whether
+// the fix is valid, and its quality if the mode indicator is known.
+func (r RMC) GeoJSON() ([]byte, error) {
+	props := map[string]interface{}{
+		"timestamp": r.Timestamp,
+		"speed":     r.Speed,
+		"course":    r.Angle,
+		"valid":     r.Valid(),
+	}
+	if q, ok := modeQualities[r.Mode]; ok {
+		props["quality"] = q.String()
+	}
+	return geoJSON([]float64{r.Longitude, r.Latitude}, props)
+}
+
+// GeoJSON returns the fix as a GeoJSON Feature with a Point geometry
+// including the altitude.  Its properties are the time of day, fix
+// quality, number of satellites and HDOP.
+func (g GGA) GeoJSON() ([]byte, error) {
+	return geoJSON([]float64{g.Longitude, g.Latitude, g.Altitude}, map[string]interface{}{
+		"time":       g.Taken.Format("15:04:05.999"),
+		"quality":    g.Quality.String(),
+		"satellites": g.NumSats,
+		"hdop":       g.HorizontalDilution,
+	})
+}
@@ -0,0 +1,65 @@
+package nmea
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRMCGeoJSON(t *testing.T) {
+	b, err := (RMC{
+		Timestamp: time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC),
+		Status:    'A',
+		Latitude:  37.383806,
+		Longitude: -121.989975,
+		Speed:     0.82,
+		Angle:     188.36,
+		Mode:      Autonomous,
+	}).GeoJSON()
+	if err != nil {
+		t.Fatalf("Error marshaling: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Error unmarshaling %s: %v", b, err)
+	}
+	exp := map[string]interface{}{
+		"type": "Feature",
+		"geometry": map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []interface{}{-121.989975, 37.383806},
+		},
+		"properties": map[string]interface{}{
+			"timestamp": "2006-07-11T16:22:54Z",
+			"speed":     0.82,
+			"course":    188.36,
+			"valid":     true,
+			"quality":   "gps",
+		},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected\n%v\ngot\n%v", exp, got)
+	}
+}
+
+func TestGGAGeoJSON(t *testing.T) {
+	b, err := (GGA{
+		Taken:              time.Date(0, 1, 1, 12, 35, 19, 0, time.UTC),
+		Latitude:           31.466667,
+		Longitude:          35.5,
+		Quality:            DGPSFix,
+		NumSats:            8,
+		HorizontalDilution: 0.9,
+		Altitude:           -412,
+	}).GeoJSON()
+	if err != nil {
+		t.Fatalf("Error marshaling: %v", err)
+	}
+	exp := `{"type":"Feature","geometry":{"type":"Point","coordinates":[35.5,31.466667,-412]},` +
+		`"properties":{"hdop":0.9,"quality":"dgps","satellites":8,"time":"12:35:19"}}`
+	if string(b) != exp {
+		t.Errorf("Expected\n%s\ngot\n%s", exp, b)
+	}
+}
@@ -0,0 +1,95 @@
+package nmea
+
+import (
+	"math"
+	"sort"
+)
+
+// A GridCell is a populated cell of a GridAggregator.
+type GridCell struct {
+	// Lat and Lon identify the south-west corner of the cell.
+	Lat, Lon float64
+	Count    int
+}
+
+type gridKey struct {
+	row, col int
+}
+
+// GridAggregator counts fixes in a grid of CellSize by CellSize
+// degree cells.
+//
+// Cells are aligned to the south-west corner at (-90, -180).
+// Longitudes are normalized into [-180, 180), so 180 and -180 fall in
+// the same cell, and the north pole is counted in the northernmost
+// row.  Cells are rectangular in degrees, so they cover less ground
+// toward the poles, and cells touching the antimeridian are not
+// merged across it.
+//
+// GridAggregator handles both RMC and GGA messages; a stream carrying
+// both will count each fix twice, so wire up only the one you want.
+// Void RMC fixes and invalid GGA fixes are not counted.
+type GridAggregator struct {
+	// CellSize is the width and height of a cell in degrees.
+	CellSize float64
+
+	cells map[gridKey]int
+}
+
+func (g *GridAggregator) add(lat, lon float64) {
+	if g.CellSize <= 0 {
+		return
+	}
+	if g.cells == nil {
+		g.cells = map[gridKey]int{}
+	}
+
+	rows := int(math.Ceil(180 / g.CellSize))
+	row := int(math.Floor((lat + 90) / g.CellSize))
+	if row >= rows {
+		row = rows - 1
+	}
+	col := int(math.Floor((normalizeLon(lon) + 180) / g.CellSize))
+	g.cells[gridKey{row, col}]++
+}
+
+// HandleRMC satisfies RMCHandler.
+func (g *GridAggregator) HandleRMC(r RMC) {
+	if !r.Valid() {
+		return
+	}
+	g.add(r.Latitude, r.Longitude)
+}
+
+// HandleGGA satisfies GGAHandler.
+func (g *GridAggregator) HandleGGA(m GGA) {
+	if !m.Valid() {
+		return
+	}
+	g.add(m.Latitude, m.Longitude)
+}
+
+// Cells returns the populated cells ordered from south-west to
+// north-east.
+func (g *GridAggregator) Cells() []GridCell {
+	keys := make([]gridKey, 0, len(g.cells))
+	for k := range g.cells {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].row != keys[j].row {
+			return keys[i].row < keys[j].row
+		}
+		return keys[i].col < keys[j].col
+	})
+
+	rv := make([]GridCell, 0, len(keys))
+	for _, k := range keys {
+		rv = append(rv, GridCell{
+			Lat:   float64(k.row)*g.CellSize - 90,
+			Lon:   float64(k.col)*g.CellSize - 180,
+			Count: g.cells[k],
+		})
+	}
+	return rv
+}
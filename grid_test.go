@@ -0,0 +1,36 @@
+package nmea
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGridAggregator(t *testing.T) {
+	g := &GridAggregator{CellSize: 0.5}
+
+	// A cluster around Santa Clara.
+	for _, p := range [][2]float64{
+		{37.38, -121.98}, {37.39, -121.99}, {37.25, -121.6}, {37.49, -121.51},
+	} {
+		g.HandleRMC(RMC{Status: 'A', Latitude: p[0], Longitude: p[1]})
+	}
+	g.HandleRMC(RMC{Status: 'V', Latitude: 37.38, Longitude: -121.98})
+	// And one more from a GGA.
+	g.HandleGGA(GGA{Quality: GPSFix, Latitude: 37.3, Longitude: -121.9})
+	g.HandleGGA(GGA{Quality: InvalidFix, Latitude: 37.3, Longitude: -121.9})
+
+	// Edge cases.
+	g.HandleRMC(RMC{Status: 'A', Latitude: 90, Longitude: 180})
+	g.HandleRMC(RMC{Status: 'A', Latitude: -90, Longitude: -180})
+	g.HandleRMC(RMC{Status: 'A', Latitude: -89.9, Longitude: 179.9})
+
+	exp := []GridCell{
+		{-90, -180, 1},
+		{-90, 179.5, 1},
+		{37, -122, 5},
+		{89.5, -180, 1},
+	}
+	if got := g.Cells(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %v, got %v", exp, got)
+	}
+}
@@ -0,0 +1,279 @@
+package nmea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// JSONLHandler writes each message it handles to a writer as a line
+// of JSON (JSON Lines), for tools such as jq.
+//
+// Each line is an object holding a "type" field naming the message
+// type, followed by the message's fields, e.g.
+//
+//	{"type":"RMC","Timestamp":"2006-07-11T16:22:54Z","Status":"A",...}
+//
+// Fields are encoded as by encoding/json, except that single letter
+// fields such as RMC.Status and Mode are written as strings, other
+// enumerations such as FixQuality by name, and nil slices such as an
+// empty ExtraFields are left out.
+//
+// Messages are written as the Processor given the JSONLHandler
+// parses them, so its settings such as Strict apply.  JSONLHandler
+// handles every type, and may be combined with other handlers using
+// MultiHandler.
+type JSONLHandler struct {
+	// W receives each record.
+	W io.Writer
+
+	err error
+}
+
+// Err returns the first error encoding or writing a record, if any.
+// Nothing more is written after an error.
+func (j *JSONLHandler) Err() error {
+	return j.err
+}
+
+// write writes a record of type typ for msg.
+func (j *JSONLHandler) write(typ string, msg interface{}) {
+	if j.err != nil {
+		return
+	}
+	rec, _ := json.Marshal(typ)
+	rec = append([]byte(`{"type":`), rec...)
+	if rec, j.err = appendJSONFields(rec, reflect.ValueOf(msg)); j.err != nil {
+		return
+	}
+	_, j.err = j.W.Write(append(rec, '}', '\n'))
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// appendJSONFields appends the exported fields of the struct v, each
+// preceded by a comma, flattening embedded structs.
+func appendJSONFields(b []byte, v reflect.Value) ([]byte, error) {
+	var err error
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := v.Type().Field(i), v.Field(i)
+		switch {
+		case f.Anonymous && fv.Kind() == reflect.Struct:
+			b, err = appendJSONFields(b, fv)
+		case f.PkgPath != "" || (fv.Kind() == reflect.Slice && fv.IsNil()):
+		default:
+			name, _ := json.Marshal(f.Name)
+			b = append(append(append(b, ','), name...), ':')
+			b, err = appendJSON(b, fv)
+		}
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// appendJSON appends the encoding of v described for JSONLHandler.
+func appendJSON(b []byte, v reflect.Value) ([]byte, error) {
+	if v.Type().Implements(jsonMarshaler) {
+		return appendMarshaled(b, v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.Int32:
+		s := ""
+		if v.Int() != 0 {
+			s = string(rune(v.Int()))
+		}
+		return appendMarshaled(b, s)
+	case reflect.Struct:
+		b = append(b, '{')
+		start := len(b)
+		b, err := appendJSONFields(b, v)
+		if err == nil && len(b) > start {
+			b = append(b[:start], b[start+1:]...)
+		}
+		return append(b, '}'), err
+	case reflect.Slice:
+		b = append(b, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = appendJSON(b, v.Index(i)); err != nil {
+				return b, err
+			}
+		}
+		return append(b, ']'), nil
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return appendMarshaled(b, s.String())
+	}
+	return appendMarshaled(b, v.Interface())
+}
+
+func appendMarshaled(b []byte, x interface{}) ([]byte, error) {
+	m, err := json.Marshal(x)
+	return append(b, m...), err
+}
+
+// HandleGGA satisfies GGAHandler.
+func (j *JSONLHandler) HandleGGA(x GGA) {
+	j.write("GGA", x)
+}
+
+// HandleGLL satisfies GLLHandler.
+func (j *JSONLHandler) HandleGLL(x GLL) {
+	j.write("GLL", x)
+}
+
+// HandleGSA satisfies GSAHandler.
+func (j *JSONLHandler) HandleGSA(x GSA) {
+	j.write("GSA", x)
+}
+
+// HandleGSV satisfies GSVHandler.
+func (j *JSONLHandler) HandleGSV(x GSV) {
+	j.write("GSV", x)
+}
+
+// HandleRMC satisfies RMCHandler.
+func (j *JSONLHandler) HandleRMC(x RMC) {
+	j.write("RMC", x)
+}
+
+// HandleVTG satisfies VTGHandler.
+func (j *JSONLHandler) HandleVTG(x VTG) {
+	j.write("VTG", x)
+}
+
+// HandleZDA satisfies ZDAHandler.
+func (j *JSONLHandler) HandleZDA(x ZDA) {
+	j.write("ZDA", x)
+}
+
+// HandleRTE satisfies RTEHandler.
+func (j *JSONLHandler) HandleRTE(x RTE) {
+	j.write("RTE", x)
+}
+
+// HandleWPL satisfies WPLHandler.
+func (j *JSONLHandler) HandleWPL(x WPL) {
+	j.write("WPL", x)
+}
+
+// HandleXDR satisfies XDRHandler.
+func (j *JSONLHandler) HandleXDR(x XDR) {
+	j.write("XDR", x)
+}
+
+// HandleVWR satisfies VWRHandler.
+func (j *JSONLHandler) HandleVWR(x VWR) {
+	j.write("VWR", x)
+}
+
+// HandleVWT satisfies VWTHandler.
+func (j *JSONLHandler) HandleVWT(x VWT) {
+	j.write("VWT", x)
+}
+
+// HandleDBT satisfies DBTHandler.
+func (j *JSONLHandler) HandleDBT(x DBT) {
+	j.write("DBT", x)
+}
+
+// HandleDBK satisfies DBKHandler.
+func (j *JSONLHandler) HandleDBK(x DBK) {
+	j.write("DBK", x)
+}
+
+// HandleDBS satisfies DBSHandler.
+func (j *JSONLHandler) HandleDBS(x DBS) {
+	j.write("DBS", x)
+}
+
+// HandleTHS satisfies THSHandler.
+func (j *JSONLHandler) HandleTHS(x THS) {
+	j.write("THS", x)
+}
+
+// HandleXTE satisfies XTEHandler.
+func (j *JSONLHandler) HandleXTE(x XTE) {
+	j.write("XTE", x)
+}
+
+// HandleXTC satisfies XTCHandler.
+func (j *JSONLHandler) HandleXTC(x XTC) {
+	j.write("XTC", x)
+}
+
+// HandleWCV satisfies WCVHandler.
+func (j *JSONLHandler) HandleWCV(x WCV) {
+	j.write("WCV", x)
+}
+
+// HandleSTN satisfies STNHandler.
+func (j *JSONLHandler) HandleSTN(x STN) {
+	j.write("STN", x)
+}
+
+// HandleMSS satisfies MSSHandler.
+func (j *JSONLHandler) HandleMSS(x MSS) {
+	j.write("MSS", x)
+}
+
+// HandleMSK satisfies MSKHandler.
+func (j *JSONLHandler) HandleMSK(x MSK) {
+	j.write("MSK", x)
+}
+
+// HandleVDR satisfies VDRHandler.
+func (j *JSONLHandler) HandleVDR(x VDR) {
+	j.write("VDR", x)
+}
+
+// HandleRSA satisfies RSAHandler.
+func (j *JSONLHandler) HandleRSA(x RSA) {
+	j.write("RSA", x)
+}
+
+// HandleAPA satisfies APAHandler.
+func (j *JSONLHandler) HandleAPA(x APA) {
+	j.write("APA", x)
+}
+
+// HandleTTM satisfies TTMHandler.
+func (j *JSONLHandler) HandleTTM(x TTM) {
+	j.write("TTM", x)
+}
+
+// HandleTLL satisfies TLLHandler.
+func (j *JSONLHandler) HandleTLL(x TLL) {
+	j.write("TLL", x)
+}
+
+// HandleAISPosition satisfies AISPositionHandler.
+func (j *JSONLHandler) HandleAISPosition(x AISPosition) {
+	j.write("AISPosition", x)
+}
+
+// HandlePUBX00 satisfies PUBX00Handler.
+func (j *JSONLHandler) HandlePUBX00(x PUBX00) {
+	j.write("PUBX00", x)
+}
+
+// HandlePGRME satisfies PGRMEHandler.
+func (j *JSONLHandler) HandlePGRME(x PGRME) {
+	j.write("PGRME", x)
+}
+
+// HandleGST satisfies GSTHandler.
+func (j *JSONLHandler) HandleGST(x GST) {
+	j.write("GST", x)
+}
+
+// HandleVPW satisfies VPWHandler.
+func (j *JSONLHandler) HandleVPW(x VPW) {
+	j.write("VPW", x)
+}
@@ -0,0 +1,73 @@
+package nmea
+
+import (
+	"bufio"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonlRecords decodes the records written by a JSONLHandler,
+// returning their types and the last record of each type.
+func jsonlRecords(t *testing.T, out string) ([]string, map[string]map[string]interface{}) {
+	var types []string
+	recs := map[string]map[string]interface{}{}
+	s := bufio.NewScanner(strings.NewReader(out))
+	for s.Scan() {
+		var rec map[string]interface{}
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			t.Fatalf("Invalid record %q: %v", s.Text(), err)
+		}
+		typ, _ := rec["type"].(string)
+		types = append(types, typ)
+		recs[typ] = rec
+	}
+	return types, recs
+}
+
+func TestJSONLHandler(t *testing.T) {
+	buf := &strings.Builder{}
+	h := &JSONLHandler{W: buf}
+	if err := Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if h.Err() != nil {
+		t.Errorf("Unexpected write error: %v", h.Err())
+	}
+
+	types, recs := jsonlRecords(t, buf.String())
+	exp := []string{"RMC", "VTG", "GGA", "GSA", "GSV", "GSV", "GSV", "GSV", "GLL", "ZDA"}
+	if !reflect.DeepEqual(types, exp) {
+		t.Errorf("Expected records of types %v, got %v", exp, types)
+	}
+	if gga := recs["GGA"]; gga["Altitude"] != 525.6 || gga["Taken"] != "0000-01-01T16:22:54Z" ||
+		gga["Quality"] != "gps" {
+		t.Errorf("Unexpected GGA record: %v", gga)
+	}
+	rmc := recs["RMC"]
+	if rmc["Status"] != "A" || rmc["Mode"] != "A" || rmc["Magvar"] != 0.0 {
+		t.Errorf("Unexpected RMC record: %v", rmc)
+	}
+	if _, ok := rmc["ExtraFields"]; ok {
+		t.Errorf("Expected no ExtraFields in %v", rmc)
+	}
+	if gsv := recs["GSV"]; len(gsv["SatInfo"].([]interface{})) != 2 {
+		t.Errorf("Unexpected GSV record: %v", gsv)
+	}
+}
+
+func TestJSONLHandlerProcessor(t *testing.T) {
+	buf := &strings.Builder{}
+	h := &JSONLHandler{W: buf}
+	p := &Processor{RejectQualities: []FixQuality{GPSFix}}
+	if err := p.Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	types, _ := jsonlRecords(t, buf.String())
+	exp := []string{"VTG", "GSA", "GSV", "GSV", "GSV", "GSV", "GLL", "ZDA"}
+	if !reflect.DeepEqual(types, exp) {
+		t.Errorf("Expected records of types %v, got %v", exp, types)
+	}
+}
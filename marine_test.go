@@ -0,0 +1,549 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type xdrHandler struct {
+	xdr XDR
+}
+
+func (x *xdrHandler) HandleXDR(xdr XDR) {
+	x.xdr = xdr
+}
+
+func TestXDRHandling(t *testing.T) {
+	h := &xdrHandler{}
+	err := (&Processor{}).parseMessage("$YXXDR,C,19.52,C,TEMP,P,1.02481,B,PRESS,H,52.7,P,RH*0B", h)
+	if err != nil {
+		t.Fatalf("Error parsing XDR: %v", err)
+	}
+	exp := []XDRMeasurement{
+		{'C', 19.52, 'C', "TEMP"},
+		{'P', 1.02481, 'B', "PRESS"},
+		{'H', 52.7, 'P', "RH"},
+	}
+	if !reflect.DeepEqual(h.xdr.Measurements, exp) {
+		t.Errorf("Expected %+v, got %+v", exp, h.xdr.Measurements)
+	}
+
+	if err := (&Processor{}).parseMessage("$YXXDR,C,x,C,TEMP*3B", h); err == nil {
+		t.Errorf("Expected error parsing a bad measurement")
+	}
+}
+
+type windHandler struct {
+	vwr VWR
+	vwt VWT
+	vpw VPW
+}
+
+func (w *windHandler) HandleVWR(vwr VWR) {
+	w.vwr = vwr
+}
+
+func (w *windHandler) HandleVWT(vwt VWT) {
+	w.vwt = vwt
+}
+
+func (w *windHandler) HandleVPW(vpw VPW) {
+	w.vpw = vpw
+}
+
+func TestWindHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp Wind
+		f   func(*windHandler) Wind
+	}{
+		{"$IIVWR,084,R,10.1,N,5.2,M,18.7,K*52", Wind{84, 'R', 10.1, 5.2, 18.7},
+			func(h *windHandler) Wind { return h.vwr.Wind }},
+		{"$IIVWT,030,L,12.0,N,6.2,M,22.2,K*49", Wind{30, 'L', 12, 6.2, 22.2},
+			func(h *windHandler) Wind { return h.vwt.Wind }},
+	}
+
+	for _, test := range tests {
+		h := &windHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if got := test.f(h); got != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, got)
+		}
+	}
+
+	err := (&Processor{}).parseMessage("$IIVWR,084,R,10.1,K,5.2,M,18.7,K*57", &windHandler{})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected ParseError on bad unit marker, got %v", err)
+	}
+}
+
+func TestVPWHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp VPW
+	}{
+		{"$IIVPW,1.2,N,2.2,K*57", VPW{1.2, 2.2, 0}},
+		{"$IIVPW,-3.5,N,-6.5,K*51", VPW{-3.5, -6.5, 0}},
+		{"$IIVPW,1.2,N,,K*79", VPW{Knots: 1.2}},
+	}
+	for _, test := range tests {
+		h := &windHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.vpw != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.vpw)
+		}
+	}
+
+	err := (&Processor{}).parseMessage("$IIVPW,1.2,K,2.2,N*57", &windHandler{})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected ParseError on swapped unit markers, got %v", err)
+	}
+}
+
+type depthHandler struct {
+	msg interface{}
+}
+
+func (d *depthHandler) HandleDBT(m DBT) { d.msg = m }
+func (d *depthHandler) HandleDBK(m DBK) { d.msg = m }
+func (d *depthHandler) HandleDBS(m DBS) { d.msg = m }
+
+func TestDepthHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp interface{}
+	}{
+		{"$SDDBT,7.8,f,2.4,M,1.3,F*0D", DBT{Depth: Depth{7.8, 2.4, 1.3}}},
+		{"$SDDBK,4.5,f,1.4,M,0.7,F*1A", DBK{Depth: Depth{4.5, 1.4, 0.7}}},
+		{"$SDDBS,11.2,f,3.4,M,1.9,F*3C", DBS{Depth: Depth{11.2, 3.4, 1.9}}},
+	}
+
+	for _, test := range tests {
+		h := &depthHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.msg != test.exp {
+			t.Errorf("On %q, expected %#v, got %#v", test.in, test.exp, h.msg)
+		}
+	}
+
+	if err := (&Processor{}).parseMessage("$SDDBT,7.8,f,2.4,f,1.3,F*26", &depthHandler{}); err == nil {
+		t.Errorf("Expected error on bad unit marker")
+	}
+}
+
+type thsHandler struct {
+	ths THS
+}
+
+func (h *thsHandler) HandleTHS(ths THS) {
+	h.ths = ths
+}
+
+func TestTHSHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp THS
+	}{
+		{"$GPTHS,338.01,A*0E", THS{Heading: 338.01, Status: 'A'}},
+		{"$GPTHS,12.5,E*05", THS{Heading: 12.5, Status: 'E'}},
+	}
+	for _, test := range tests {
+		h := &thsHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.ths != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.ths)
+		}
+	}
+
+	for _, in := range []string{"$GPTHS,12.5,X*18", "$GPTHS,12.5,*40"} {
+		if err := (&Processor{}).parseMessage(in, &thsHandler{}); err == nil {
+			t.Errorf("Expected error parsing %q", in)
+		}
+	}
+}
+
+func TestTrueWind(t *testing.T) {
+	tests := []struct {
+		speed, heading, awa, aws float64
+		twd, tws                 float64
+	}{
+		// Head to wind, the boat's speed just adds on.
+		{5, 0, 0, 15, 0, 10},
+		// Apparent wind on the beam.
+		{10, 0, 90, 10, 135, 14.142136},
+		{10, 350, 90, 10, 125, 14.142136},
+		// Close hauled on port.
+		{6, 90, 315, 12, 16.324950, 8.841755},
+		// Stationary, apparent is true.
+		{0, 180, 270, 8, 90, 8},
+		// Running dead downwind faster than the wind.
+		{8, 0, 0, 2, 180, 6},
+	}
+
+	for _, test := range tests {
+		twd, tws := TrueWind(test.speed, test.heading, test.awa, test.aws)
+		if !near(twd, test.twd) || !near(tws, test.tws) {
+			t.Errorf("TrueWind(%v, %v, %v, %v) = %v, %v; want %v, %v",
+				test.speed, test.heading, test.awa, test.aws, twd, tws, test.twd, test.tws)
+		}
+	}
+}
+
+type crossTrackHandler struct {
+	msg interface{}
+}
+
+func (c *crossTrackHandler) HandleXTE(m XTE) { c.msg = m }
+func (c *crossTrackHandler) HandleXTC(m XTC) { c.msg = m }
+
+func TestCrossTrackHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp interface{}
+	}{
+		{"$GPXTE,A,A,0.67,L,N,A*02", XTE{true, true, CrossTrack{0.67, 'L', 'N'}, Autonomous, 0}},
+		{"$GPXTE,V,V,,,N*3C", XTE{false, false, CrossTrack{0, 0, 'N'}, 0, 0}},
+		{"$GPXTC,0.67,L,N*69", XTC{CrossTrack{0.67, 'L', 'N'}, 0}},
+		{"$GPXTC,1.2,R,K*40", XTC{CrossTrack{1.2, 'R', 'K'}, 0}},
+	}
+
+	for _, test := range tests {
+		h := &crossTrackHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.msg != test.exp {
+			t.Errorf("On %q, expected %#v, got %#v", test.in, test.exp, h.msg)
+		}
+	}
+
+	for _, in := range []string{"$GPXTC,1.2,X,K*4A", "$GPXTE,A,A,0.67,L,M*6C"} {
+		if err := (&Processor{}).parseMessage(in, &crossTrackHandler{}); err == nil {
+			t.Errorf("Expected error parsing %q", in)
+		}
+	}
+}
+
+type wcvHandler struct {
+	wcv WCV
+}
+
+func (w *wcvHandler) HandleWCV(wcv WCV) {
+	w.wcv = wcv
+}
+
+func TestWCVHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp WCV
+	}{
+		{"$GPWCV,0.0,N,DEST*1F", WCV{0, 'N', "DEST", 0, 0}},
+		{"$GPWCV,4.5,N,WPT01,A*27", WCV{4.5, 'N', "WPT01", Autonomous, 0}},
+	}
+	for _, test := range tests {
+		h := &wcvHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.wcv != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.wcv)
+		}
+	}
+
+	if err := (&Processor{}).parseMessage("$GPWCV,4.5,K,WPT01*4F", &wcvHandler{}); err == nil {
+		t.Errorf("Expected error on bad unit marker")
+	}
+}
+
+type stnHandler struct {
+	stn STN
+}
+
+func (s *stnHandler) HandleSTN(stn STN) {
+	s.stn = stn
+}
+
+type dataIDRecorder struct {
+	stnHandler
+	ids []int
+}
+
+func (d *dataIDRecorder) HandleDBT(m DBT) { d.ids = append(d.ids, m.DataID) }
+func (d *dataIDRecorder) HandleRMC(m RMC) { d.ids = append(d.ids, m.DataID) }
+func (d *dataIDRecorder) HandleXTE(m XTE) { d.ids = append(d.ids, m.DataID) }
+func (d *dataIDRecorder) HandleVDR(m VDR) { d.ids = append(d.ids, m.DataID) }
+func (d *dataIDRecorder) HandleTTM(m TTM) { d.ids = append(d.ids, m.DataID) }
+func (d *dataIDRecorder) HandleGST(m GST) { d.ids = append(d.ids, m.DataID) }
+
+func TestSTNDataID(t *testing.T) {
+	input := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" +
+		"$SDDBT,7.8,f,2.4,M,1.3,F*0D\n" +
+		"$GPSTN,01*73\n" +
+		"$SDDBT,7.8,f,2.4,M,1.3,F*0D\n" +
+		"$GPSTN,02*70\n" +
+		"$SDDBT,7.8,f,2.4,M,1.3,F*0D\n" +
+		"$GPRMC,162255.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*75\n" +
+		"$SDDBT,7.8,f,2.4,M,1.3,F*0D\n"
+
+	h := &dataIDRecorder{}
+	err := Process(strings.NewReader(input), h, func(s string, err error) error { return err })
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if exp := []int{0, 0, 1, 2, 0, 0}; !reflect.DeepEqual(h.ids, exp) {
+		t.Errorf("Expected data IDs %v, got %v", exp, h.ids)
+	}
+	if h.stn.DataID != 2 {
+		t.Errorf("Expected last STN to be handled, got %+v", h.stn)
+	}
+}
+
+func TestSTNBeforeNewCycle(t *testing.T) {
+	input := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" +
+		"$GPSTN,01*73\n" +
+		"$GPRMC,162255.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*75\n" +
+		"$SDDBT,7.8,f,2.4,M,1.3,F*0D\n" +
+		"$GPRMC,162256.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*76\n"
+
+	h := &dataIDRecorder{}
+	err := Process(strings.NewReader(input), h, func(s string, err error) error { return err })
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if exp := []int{0, 1, 1, 0}; !reflect.DeepEqual(h.ids, exp) {
+		t.Errorf("Expected data IDs %v, got %v", exp, h.ids)
+	}
+}
+
+func TestSTNDataIDSensors(t *testing.T) {
+	input := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" +
+		"$GPSTN,03*71\n" +
+		"$GPXTE,A,A,0.67,L,N,A*02\n" +
+		"$IIVDR,010.0,T,011.0,M,02.5,N*0F\n" +
+		"$RATTM,02,1.5,270.0,R,0.0,0.0,T,1.5,-3.0,N,,Q,R*10\n" +
+		"$GPGST,162254.00,3.2,6.6,4.7,47.3,5.8,5.6,22.0*5D\n"
+
+	h := &dataIDRecorder{}
+	err := Process(strings.NewReader(input), h, func(s string, err error) error { return err })
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if exp := []int{0, 3, 3, 3, 3}; !reflect.DeepEqual(h.ids, exp) {
+		t.Errorf("Expected data IDs %v, got %v", exp, h.ids)
+	}
+}
+
+type beaconHandler struct {
+	msg interface{}
+}
+
+func (b *beaconHandler) HandleMSS(m MSS) { b.msg = m }
+func (b *beaconHandler) HandleMSK(m MSK) { b.msg = m }
+
+func TestBeaconHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp interface{}
+	}{
+		{"$GPMSS,55,27,320.0,100,*6D", MSS{55, 27, 320, 100, 0, 0}},
+		{"$GPMSS,55,27,320.0,100,1*5C", MSS{55, 27, 320, 100, 1, 0}},
+		{"$GPMSK,318.0,A,100,M,2*45", MSK{318, true, 100, false, 2}},
+	}
+	for _, test := range tests {
+		h := &beaconHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.msg != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.msg)
+		}
+	}
+
+	if err := (&Processor{}).parseMessage("$GPMSS,55,27,320.0,x,*24", &beaconHandler{}); err == nil {
+		t.Errorf("Expected error on bad bit rate")
+	}
+}
+
+type vdrHandler struct {
+	vdr VDR
+}
+
+func (v *vdrHandler) HandleVDR(m VDR) { v.vdr = m }
+
+func TestVDRHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp VDR
+	}{
+		{"$IIVDR,010.0,T,011.0,M,02.5,N*0F", VDR{10, 11, 2.5, 0}},
+		{"$IIVDR,010.0,T,,M,,N*38", VDR{DirectionTrue: 10}},
+	}
+	for _, test := range tests {
+		h := &vdrHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.vdr != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.vdr)
+		}
+	}
+
+	if err := (&Processor{}).parseMessage("$IIVDR,010.0,T,011.0,M,02.5,K*0A", &vdrHandler{}); err == nil {
+		t.Errorf("Expected error on bad speed unit")
+	}
+}
+
+type rsaHandler struct {
+	rsa RSA
+}
+
+func (r *rsaHandler) HandleRSA(m RSA) { r.rsa = m }
+
+func TestRSAHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp RSA
+	}{
+		{"$ERRSA,1.2,A,-0.5,A*7C", RSA{1.2, true, -0.5, true, 0}},
+		{"$ERRSA,-3.4,A,,V*44", RSA{Starboard: -3.4, StarboardValid: true}},
+	}
+	for _, test := range tests {
+		h := &rsaHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.rsa != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.rsa)
+		}
+	}
+
+	if err := (&Processor{}).parseMessage("$ERRSA,x,A,,V*38", &rsaHandler{}); err == nil {
+		t.Errorf("Expected error on bad angle")
+	}
+}
+
+type apaHandler struct {
+	apa APA
+}
+
+func (a *apaHandler) HandleAPA(m APA) { a.apa = m }
+
+func TestAPAHandling(t *testing.T) {
+	h := &apaHandler{}
+	if err := (&Processor{}).parseMessage("$GPAPA,A,A,0.10,R,N,V,V,011,M,DEST*3F", h); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	exp := APA{Autopilot: Autopilot{
+		Valid:         true,
+		CycleLock:     true,
+		CrossTrack:    CrossTrack{0.1, 'R', 'N'},
+		Bearing:       11,
+		BearingRef:    'M',
+		DestinationID: "DEST",
+	}}
+	if h.apa != exp {
+		t.Errorf("Expected %+v, got %+v", exp, h.apa)
+	}
+
+	err := (&Processor{}).parseMessage("$GPAPA,A,A,0.10,R,N,A,A,011,X,DEST*2A", &apaHandler{})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected a ParseError on bad bearing reference, got %v", err)
+	}
+}
+
+type targetHandler struct {
+	ttm TTM
+	tll TLL
+}
+
+func (h *targetHandler) HandleTTM(m TTM) { h.ttm = m }
+func (h *targetHandler) HandleTLL(m TLL) { h.tll = m }
+
+func TestTTMHandling(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp TTM
+	}{
+		{"$RATTM,11,25.3,13.7,T,7.0,20.0,T,10.1,20.2,N,TGT,T,,100021.00,A*76", TTM{
+			Number:      11,
+			Distance:    25.3,
+			Bearing:     13.7,
+			BearingRef:  'T',
+			Speed:       7,
+			Course:      20,
+			CourseRef:   'T',
+			CPA:         10.1,
+			TCPA:        20.2,
+			Unit:        'N',
+			Name:        "TGT",
+			Status:      'T',
+			Taken:       time.Date(0, 1, 1, 10, 0, 21, 0, time.UTC),
+			Acquisition: 'A',
+		}},
+		// Pre-3.0 units stop at the reference target field.
+		{"$RATTM,02,1.5,270.0,R,0.0,0.0,T,1.5,-3.0,N,,Q,R*10", TTM{
+			Number:     2,
+			Distance:   1.5,
+			Bearing:    270,
+			BearingRef: 'R',
+			CourseRef:  'T',
+			CPA:        1.5,
+			TCPA:       -3,
+			Unit:       'N',
+			Status:     'Q',
+			Reference:  true,
+		}},
+	}
+	for _, test := range tests {
+		h := &targetHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.ttm != test.exp {
+			t.Errorf("On %q, expected %+v, got %+v", test.in, test.exp, h.ttm)
+		}
+	}
+}
+
+func TestTLLHandling(t *testing.T) {
+	h := &targetHandler{}
+	if err := (&Processor{}).parseMessage("$RATLL,01,4807.038,N,01131.000,E,TGT,123519.00,T,*63", h); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	exp := TLL{
+		Number:    1,
+		Latitude:  48.1173,
+		Longitude: 11.516666666666667,
+		Name:      "TGT",
+		Taken:     time.Date(0, 1, 1, 12, 35, 19, 0, time.UTC),
+		Status:    'T',
+	}
+	if !similar(t, h.tll, exp) {
+		t.Errorf("Expected more similarity between %+v and (wanted) %+v", h.tll, exp)
+	}
+
+	err := (&Processor{}).parseMessage("$RATLL,01,4807.038,N,01131.000,E,TGT,123519.00,X,*6F", &targetHandler{})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected a ParseError on bad status, got %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package nmea
+
+import "time"
+
+// MultiHandler returns a handler passing every sentence to each of
+// handlers that handles its type, in order.  It lets independent
+// consumers share a single stream, e.g.
+//
+//	nmea.Process(r, nmea.MultiHandler(kml, dashboard), nil)
+//
+// Handlers may be RawHandlers, EpochHandlers, Flushers or wrappers
+// such as TeeHandler.  Since the returned handler handles every
+// type, errors are reported for sentences of any type none of
+// handlers wanted.
+func MultiHandler(handlers ...interface{}) interface{} {
+	m := &multiHandler{outer: handlers}
+	for _, h := range handlers {
+		m.inner = append(m.inner, unwrap(h))
+	}
+	return m
+}
+
+// multiHandler holds the handlers given to MultiHandler, and the
+// handlers they wrap, which receive parsed sentences.
+type multiHandler struct {
+	outer, inner []interface{}
+}
+
+// HandleRaw satisfies RawHandler.
+func (m *multiHandler) HandleRaw(s string) {
+	for _, h := range m.outer {
+		if h, ok := h.(RawHandler); ok {
+			h.HandleRaw(s)
+		}
+	}
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (m *multiHandler) HandleEpoch(t time.Time) {
+	for _, h := range m.inner {
+		if h, ok := h.(EpochHandler); ok {
+			h.HandleEpoch(t)
+		}
+	}
+}
+
+// Flush satisfies Flusher.
+func (m *multiHandler) Flush() {
+	for _, h := range m.outer {
+		flush(h)
+	}
+}
+
+// HandleGGA satisfies GGAHandler.
+func (m *multiHandler) HandleGGA(x GGA) {
+	for _, h := range m.inner {
+		if h, ok := h.(GGAHandler); ok {
+			h.HandleGGA(x)
+		}
+	}
+}
+
+// HandleGLL satisfies GLLHandler.
+func (m *multiHandler) HandleGLL(x GLL) {
+	for _, h := range m.inner {
+		if h, ok := h.(GLLHandler); ok {
+			h.HandleGLL(x)
+		}
+	}
+}
+
+// HandleGSA satisfies GSAHandler.
+func (m *multiHandler) HandleGSA(x GSA) {
+	for _, h := range m.inner {
+		if h, ok := h.(GSAHandler); ok {
+			h.HandleGSA(x)
+		}
+	}
+}
+
+// HandleGSV satisfies GSVHandler.
+func (m *multiHandler) HandleGSV(x GSV) {
+	for _, h := range m.inner {
+		if h, ok := h.(GSVHandler); ok {
+			h.HandleGSV(x)
+		}
+	}
+}
+
+// HandleRMC satisfies RMCHandler.
+func (m *multiHandler) HandleRMC(x RMC) {
+	for _, h := range m.inner {
+		if h, ok := h.(RMCHandler); ok {
+			h.HandleRMC(x)
+		}
+	}
+}
+
+// HandleVTG satisfies VTGHandler.
+func (m *multiHandler) HandleVTG(x VTG) {
+	for _, h := range m.inner {
+		if h, ok := h.(VTGHandler); ok {
+			h.HandleVTG(x)
+		}
+	}
+}
+
+// HandleZDA satisfies ZDAHandler.
+func (m *multiHandler) HandleZDA(x ZDA) {
+	for _, h := range m.inner {
+		if h, ok := h.(ZDAHandler); ok {
+			h.HandleZDA(x)
+		}
+	}
+}
+
+// HandleRTE satisfies RTEHandler.
+func (m *multiHandler) HandleRTE(x RTE) {
+	for _, h := range m.inner {
+		if h, ok := h.(RTEHandler); ok {
+			h.HandleRTE(x)
+		}
+	}
+}
+
+// HandleWPL satisfies WPLHandler.
+func (m *multiHandler) HandleWPL(x WPL) {
+	for _, h := range m.inner {
+		if h, ok := h.(WPLHandler); ok {
+			h.HandleWPL(x)
+		}
+	}
+}
+
+// HandleXDR satisfies XDRHandler.
+func (m *multiHandler) HandleXDR(x XDR) {
+	for _, h := range m.inner {
+		if h, ok := h.(XDRHandler); ok {
+			h.HandleXDR(x)
+		}
+	}
+}
+
+// HandleVWR satisfies VWRHandler.
+func (m *multiHandler) HandleVWR(x VWR) {
+	for _, h := range m.inner {
+		if h, ok := h.(VWRHandler); ok {
+			h.HandleVWR(x)
+		}
+	}
+}
+
+// HandleVWT satisfies VWTHandler.
+func (m *multiHandler) HandleVWT(x VWT) {
+	for _, h := range m.inner {
+		if h, ok := h.(VWTHandler); ok {
+			h.HandleVWT(x)
+		}
+	}
+}
+
+// HandleDBT satisfies DBTHandler.
+func (m *multiHandler) HandleDBT(x DBT) {
+	for _, h := range m.inner {
+		if h, ok := h.(DBTHandler); ok {
+			h.HandleDBT(x)
+		}
+	}
+}
+
+// HandleDBK satisfies DBKHandler.
+func (m *multiHandler) HandleDBK(x DBK) {
+	for _, h := range m.inner {
+		if h, ok := h.(DBKHandler); ok {
+			h.HandleDBK(x)
+		}
+	}
+}
+
+// HandleDBS satisfies DBSHandler.
+func (m *multiHandler) HandleDBS(x DBS) {
+	for _, h := range m.inner {
+		if h, ok := h.(DBSHandler); ok {
+			h.HandleDBS(x)
+		}
+	}
+}
+
+// HandleTHS satisfies THSHandler.
+func (m *multiHandler) HandleTHS(x THS) {
+	for _, h := range m.inner {
+		if h, ok := h.(THSHandler); ok {
+			h.HandleTHS(x)
+		}
+	}
+}
+
+// HandleXTE satisfies XTEHandler.
+func (m *multiHandler) HandleXTE(x XTE) {
+	for _, h := range m.inner {
+		if h, ok := h.(XTEHandler); ok {
+			h.HandleXTE(x)
+		}
+	}
+}
+
+// HandleXTC satisfies XTCHandler.
+func (m *multiHandler) HandleXTC(x XTC) {
+	for _, h := range m.inner {
+		if h, ok := h.(XTCHandler); ok {
+			h.HandleXTC(x)
+		}
+	}
+}
+
+// HandleWCV satisfies WCVHandler.
+func (m *multiHandler) HandleWCV(x WCV) {
+	for _, h := range m.inner {
+		if h, ok := h.(WCVHandler); ok {
+			h.HandleWCV(x)
+		}
+	}
+}
+
+// HandleSTN satisfies STNHandler.
+func (m *multiHandler) HandleSTN(x STN) {
+	for _, h := range m.inner {
+		if h, ok := h.(STNHandler); ok {
+			h.HandleSTN(x)
+		}
+	}
+}
+
+// HandleMSS satisfies MSSHandler.
+func (m *multiHandler) HandleMSS(x MSS) {
+	for _, h := range m.inner {
+		if h, ok := h.(MSSHandler); ok {
+			h.HandleMSS(x)
+		}
+	}
+}
+
+// HandleMSK satisfies MSKHandler.
+func (m *multiHandler) HandleMSK(x MSK) {
+	for _, h := range m.inner {
+		if h, ok := h.(MSKHandler); ok {
+			h.HandleMSK(x)
+		}
+	}
+}
+
+// HandleVDR satisfies VDRHandler.
+func (m *multiHandler) HandleVDR(x VDR) {
+	for _, h := range m.inner {
+		if h, ok := h.(VDRHandler); ok {
+			h.HandleVDR(x)
+		}
+	}
+}
+
+// HandleRSA satisfies RSAHandler.
+func (m *multiHandler) HandleRSA(x RSA) {
+	for _, h := range m.inner {
+		if h, ok := h.(RSAHandler); ok {
+			h.HandleRSA(x)
+		}
+	}
+}
+
+// HandleAPA satisfies APAHandler.
+func (m *multiHandler) HandleAPA(x APA) {
+	for _, h := range m.inner {
+		if h, ok := h.(APAHandler); ok {
+			h.HandleAPA(x)
+		}
+	}
+}
+
+// HandleTTM satisfies TTMHandler.
+func (m *multiHandler) HandleTTM(x TTM) {
+	for _, h := range m.inner {
+		if h, ok := h.(TTMHandler); ok {
+			h.HandleTTM(x)
+		}
+	}
+}
+
+// HandleTLL satisfies TLLHandler.
+func (m *multiHandler) HandleTLL(x TLL) {
+	for _, h := range m.inner {
+		if h, ok := h.(TLLHandler); ok {
+			h.HandleTLL(x)
+		}
+	}
+}
+
+// HandleAISPosition satisfies AISPositionHandler.
+func (m *multiHandler) HandleAISPosition(x AISPosition) {
+	for _, h := range m.inner {
+		if h, ok := h.(AISPositionHandler); ok {
+			h.HandleAISPosition(x)
+		}
+	}
+}
+
+// HandlePUBX00 satisfies PUBX00Handler.
+func (m *multiHandler) HandlePUBX00(x PUBX00) {
+	for _, h := range m.inner {
+		if h, ok := h.(PUBX00Handler); ok {
+			h.HandlePUBX00(x)
+		}
+	}
+}
+
+// HandlePGRME satisfies PGRMEHandler.
+func (m *multiHandler) HandlePGRME(x PGRME) {
+	for _, h := range m.inner {
+		if h, ok := h.(PGRMEHandler); ok {
+			h.HandlePGRME(x)
+		}
+	}
+}
+
+// HandleGST satisfies GSTHandler.
+func (m *multiHandler) HandleGST(x GST) {
+	for _, h := range m.inner {
+		if h, ok := h.(GSTHandler); ok {
+			h.HandleGST(x)
+		}
+	}
+}
+
+// HandleVPW satisfies VPWHandler.
+func (m *multiHandler) HandleVPW(x VPW) {
+	for _, h := range m.inner {
+		if h, ok := h.(VPWHandler); ok {
+			h.HandleVPW(x)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package nmea
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMultiHandler(t *testing.T) {
+	var a, b []RMC
+	buf := &bytes.Buffer{}
+	h := MultiHandler(
+		&rmcRecorder{func(r RMC) { a = append(a, r) }},
+		&TeeHandler{W: buf, Handler: &rmcRecorder{func(r RMC) { b = append(b, r) }}},
+	)
+	if err := Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(a) != 1 || len(b) != 1 {
+		t.Errorf("Expected both handlers to see the RMC, got %v and %v", a, b)
+	}
+	if got := strings.Count(buf.String(), "\r\n"); got != 10 {
+		t.Errorf("Expected 10 sentences teed, got %v", got)
+	}
+}
+
+func TestMultiHandlerTypes(t *testing.T) {
+	// Parsers ignore sentences their handler doesn't handle, so
+	// one complaining of a short sentence was given a handler.
+	h := MultiHandler()
+	for prefix, parser := range parsers {
+		if err := parser(&Processor{}, []string{prefix}, h); err == nil {
+			t.Errorf("MultiHandler doesn't handle %v", prefix)
+		}
+	}
+}
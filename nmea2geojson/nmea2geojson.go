@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/dustin/go-nmea"
+)
+
+var (
+	useGGA = flag.Bool("gga", false, "emit GGA fixes (with altitude) rather than RMC fixes")
+	all    = flag.Bool("all", false, "include invalid fixes")
+	prec   = flag.Int("precision", 6, "decimal places in coordinates")
+)
+
+// collectionWriter streams fixes as the features of a GeoJSON
+// FeatureCollection.
+type collectionWriter struct {
+	w    io.Writer
+	gga  bool
+	all  bool
+	prec int // decimal places in coordinates
+	n    int
+	err  error
+}
+
+// round rounds a coordinate to c.prec decimal places, so it's encoded
+// with no more.
+func (c *collectionWriter) round(deg float64) float64 {
+	r, _ := strconv.ParseFloat(nmea.FormatDegrees(deg, c.prec), 64)
+	return r
+}
+
+func (c *collectionWriter) write(s string) {
+	if c.err == nil {
+		_, c.err = io.WriteString(c.w, s)
+	}
+}
+
+func (c *collectionWriter) feature(b []byte, err error) {
+	if c.err != nil {
+		return
+	}
+	if err != nil {
+		c.err = err
+		return
+	}
+	if c.n > 0 {
+		c.write(",\n")
+	}
+	c.write(string(b))
+	c.n++
+}
+
+func (c *collectionWriter) HandleRMC(m nmea.RMC) {
+	if !c.gga && (c.all || m.Valid()) {
+		m.Latitude, m.Longitude = c.round(m.Latitude), c.round(m.Longitude)
+		c.feature(m.GeoJSON())
+	}
+}
+
+func (c *collectionWriter) HandleGGA(m nmea.GGA) {
+	if c.gga && (c.all || m.Valid()) {
+		m.Latitude, m.Longitude = c.round(m.Latitude), c.round(m.Longitude)
+		c.feature(m.GeoJSON())
+	}
+}
+
+func (c *collectionWriter) Init() error {
+	c.write(`{"type":"FeatureCollection","features":[` + "\n")
+	return c.err
+}
+
+func (c *collectionWriter) Close() error {
+	c.write("\n]}\n")
+	return c.err
+}
+
+func main() {
+	flag.Parse()
+	w := bufio.NewWriter(os.Stdout)
+	h := &collectionWriter{w: w, gga: *useGGA, all: *all, prec: *prec}
+	h.Init()
+	err := nmea.Process(os.Stdin, h, func(s string, err error) error {
+		if err != nil {
+			log.Printf("On %q: %v", s, err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		log.Fatalf("Error processing stuff: %v", err)
+	}
+	if err := h.Close(); err != nil {
+		log.Fatalf("Error finishing up GeoJSON output: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Error writing GeoJSON output: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dustin/go-nmea"
+)
+
+const sample = "$GPGGA,123519,3128.000,N,03530.000,E,1,08,0.9,-412.0,M,19.2,M,,*63\n" +
+	"$GPRMC,123519,A,3128.000,N,03530.000,E,0.0,0.0,230394,,*12\n" +
+	"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n"
+
+type collection struct {
+	Type     string
+	Features []struct {
+		Type     string
+		Geometry struct {
+			Type        string
+			Coordinates []float64
+		}
+		Properties map[string]interface{}
+	}
+}
+
+func process(t *testing.T, gga bool, prec int) collection {
+	buf := &bytes.Buffer{}
+	c := &collectionWriter{w: buf, gga: gga, prec: prec}
+	c.Init()
+	if err := nmea.Process(strings.NewReader(sample), c, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Error closing: %v", err)
+	}
+
+	var got collection
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", buf, err)
+	}
+	if got.Type != "FeatureCollection" {
+		t.Errorf("Expected a FeatureCollection, got %q", got.Type)
+	}
+	for _, f := range got.Features {
+		if f.Type != "Feature" || f.Geometry.Type != "Point" {
+			t.Errorf("Expected Point Features, got %+v", f)
+		}
+	}
+	return got
+}
+
+func TestRMCCollection(t *testing.T) {
+	got := process(t, false, 6)
+	if len(got.Features) != 2 {
+		t.Fatalf("Expected 2 features, got %+v", got)
+	}
+	if c := got.Features[1].Geometry.Coordinates; len(c) != 2 || c[0] > -121 || c[1] < 37 {
+		t.Errorf("Expected [lon, lat], got %v", c)
+	}
+}
+
+func TestGGACollection(t *testing.T) {
+	got := process(t, true, 6)
+	if len(got.Features) != 1 {
+		t.Fatalf("Expected 1 feature, got %+v", got)
+	}
+	if c := got.Features[0].Geometry.Coordinates; len(c) != 3 || c[0] != 35.5 || c[2] != -412 {
+		t.Errorf("Expected [lon, lat, alt], got %v", c)
+	}
+}
+
+func TestCollectionPrecision(t *testing.T) {
+	got := process(t, false, 3)
+	if len(got.Features) != 2 {
+		t.Fatalf("Expected 2 features, got %+v", got)
+	}
+	if c := got.Features[1].Geometry.Coordinates; len(c) != 2 || c[0] != -121.99 || c[1] != 37.384 {
+		t.Errorf("Expected [-121.99, 37.384], got %v", c)
+	}
+}
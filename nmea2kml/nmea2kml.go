@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"os"
+	"strconv"
 	"time"
 
 	"text/template"
@@ -25,7 +25,7 @@ const kmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
 const kmlPoint = `<Placemark>
     <name>{{.TS}}</name>
     <TimeStamp>{{.TS}}</TimeStamp>
-    <Point><coordinates>{{.Lon}},{{.Lat}},0.0</coordinates></Point>
+    <Point><coordinates>{{.Lon}},{{.Lat}},{{.Alt}}</coordinates></Point>
 </Placemark>
 `
 
@@ -37,6 +37,9 @@ var (
 	minDist = flag.Int("minDist", 1000, "minimum distance (meters) between points")
 	minTime = flag.Duration("minTime", 1*time.Minute, "minimum time between points")
 	title   = flag.String("title", "Road Trip", "KML title")
+	prec    = flag.Int("precision", 6, "decimal places in coordinates")
+	simp    = flag.Float64("simplify", 0,
+		"if not 0, simplify the whole track to within this many meters instead of using minDist and minTime")
 
 	tmpl = template.Must(template.New("").Parse(kmlPoint))
 )
@@ -69,30 +72,93 @@ type kmlWriter struct {
 	w          errRememberer
 	plat, plon float64
 	pts        time.Time
+	alt        float64   // most recent GGA altitude
+	altAt      time.Time // time of day of alt
+	rmc        *nmea.RMC // waiting for the altitude of its cycle
+
+	track []nmea.TrackPoint // when simplifying
 }
 
-func (k *kmlWriter) render(m nmea.RMC, Δλ float64) {
+func (k *kmlWriter) render(p nmea.TrackPoint) {
 	tmpl.Execute(k.w, struct {
-		Lon, Lat float64
-		TS       string
-		D        float64
-		H        float64
-	}{m.Longitude, m.Latitude, m.Timestamp.Format(tsFormat), Δλ, m.Angle})
+		Lon, Lat, Alt string
+		TS            string
+	}{nmea.FormatDegrees(p.Longitude, *prec), nmea.FormatDegrees(p.Latitude, *prec),
+		strconv.FormatFloat(p.Altitude, 'f', 1, 64),
+		p.Time.Format(tsFormat)})
+}
+
+func (k *kmlWriter) point(m nmea.RMC) nmea.TrackPoint {
+	p := m.TrackPoint()
+	p.Altitude = k.alt
+	return p
+}
+
+// sameTime reports whether a and b have the same time of day.
+func sameTime(a, b time.Time) bool {
+	return a.Hour() == b.Hour() && a.Minute() == b.Minute() &&
+		a.Second() == b.Second() && a.Nanosecond() == b.Nanosecond()
 }
 
+// HandleGGA remembers the altitude so it can be included with the
+// RMC fix of the same time.  RMC carries no altitude of its own.
+func (k *kmlWriter) HandleGGA(m nmea.GGA) {
+	if !m.Valid() {
+		return
+	}
+	k.alt, k.altAt = m.Altitude, m.Taken
+	if k.rmc != nil && sameTime(k.rmc.Timestamp, m.Taken) {
+		k.flushRMC()
+	}
+}
+
+// HandleRMC adds valid fixes once the altitude of their cycle is
+// known, from a GGA either side of it.  Void fixes, which often
+// report (0,0), are skipped.
 func (k *kmlWriter) HandleRMC(m nmea.RMC) {
+	if !m.Valid() {
+		return
+	}
+	k.flushRMC()
+	k.rmc = &m
+	if !k.altAt.IsZero() && sameTime(m.Timestamp, k.altAt) {
+		k.flushRMC()
+	}
+}
+
+// HandleEpoch adds a fix whose cycle ended without a GGA, with the
+// most recent altitude.
+func (k *kmlWriter) HandleEpoch(time.Time) {
+	k.flushRMC()
+}
+
+// Flush adds the last fix.
+func (k *kmlWriter) Flush() {
+	k.flushRMC()
+}
+
+func (k *kmlWriter) flushRMC() {
+	if k.rmc == nil {
+		return
+	}
+	m := *k.rmc
+	k.rmc = nil
+	if *simp != 0 {
+		k.track = append(k.track, k.point(m))
+		return
+	}
 	if k.plat == 0 {
-		k.render(m, 0)
+		k.render(k.point(m))
 		k.plat = m.Latitude
 		k.plon = m.Longitude
 		k.pts = m.Timestamp
 		return
 	}
-	Δλ := distance(m.Longitude, m.Latitude, k.plon, k.plat)
+	Δλ := nmea.Distance(m.Latitude, m.Longitude, k.plat, k.plon)
 	Δt := m.Timestamp.Sub(k.pts)
-	if Δλ < float64(*minDist) && Δt > *minTime {
+	if Δλ < nmea.Meters(*minDist) && Δt > *minTime {
 		log.Printf("Δλ = %v, Δt = %v", Δλ, Δt)
-		k.render(m, Δλ)
+		k.render(k.point(m))
 	} else {
 		k.plat = m.Latitude
 		k.plon = m.Longitude
@@ -105,29 +171,15 @@ func (k kmlWriter) Init() error {
 	return k.w.err
 }
 
-func (k kmlWriter) Close() error {
+func (k *kmlWriter) Close() error {
+	k.flushRMC()
+	for _, p := range nmea.Simplify(k.track, nmea.Meters(*simp)) {
+		k.render(p)
+	}
 	k.w.Write([]byte(kmlFooter))
 	return k.w.Close()
 }
 
-func d2r(d float64) float64 {
-	return d * math.Pi / 180.0
-}
-
-func distance(lon1, lat1, lon2, lat2 float64) float64 {
-	φ1 := d2r(lat1)
-	φ2 := d2r(lat2)
-	Δφ := d2r(lat2 - lat1)
-	Δλ := d2r(lon2 - lon1)
-
-	a := math.Sin(Δφ/2)*math.Sin(Δφ/2) +
-		math.Cos(φ1)*math.Cos(φ2)*
-			math.Sin(Δλ/2)*math.Sin(Δλ/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	return 6371000 * c
-}
-
 func main() {
 	flag.Parse()
 	h := &kmlWriter{w: errRememberer{w: os.Stdout}}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dustin/go-nmea"
+)
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestRenderPrecision(t *testing.T) {
+	defer func(p int) { *prec = p }(*prec)
+	*prec = 4
+
+	buf := &bytes.Buffer{}
+	k := &kmlWriter{w: errRememberer{w: nopCloser{buf}}}
+	k.render(nmea.TrackPoint{
+		Latitude:  37.383806166666666,
+		Longitude: -121.9899755,
+		Time:      time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC),
+	})
+	if exp := "<coordinates>-121.9900,37.3838,0.0</coordinates>"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in\n%v", exp, buf)
+	}
+}
+
+func TestRenderNegativeAltitude(t *testing.T) {
+	buf := &bytes.Buffer{}
+	k := &kmlWriter{w: errRememberer{w: nopCloser{buf}}}
+	if err := nmea.Process(strings.NewReader(
+		"$GPGGA,123519,3128.000,N,03530.000,E,1,08,0.9,-412.0,M,19.2,M,,*63\n"+
+			"$GPRMC,123519,A,3128.000,N,03530.000,E,0.0,0.0,230394,,*12\n"), k, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if exp := "<coordinates>35.500000,31.466667,-412.0</coordinates>"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in\n%v", exp, buf)
+	}
+}
+
+func TestRenderAltitudePerCycle(t *testing.T) {
+	// Receivers send GGA after RMC; each point takes the altitude
+	// of its own cycle.
+	var lines []string
+	for i, ts := range []string{"123519", "123700"} {
+		lines = append(lines,
+			nmea.AppendChecksum("$GPRMC,"+ts+",A,3128.000,N,03530.000,E,0.0,0.0,230394,,"),
+			nmea.AppendChecksum(fmt.Sprintf("$GPGGA,%v,3128.000,N,03530.000,E,1,08,0.9,%d.0,M,19.2,M,,", ts, 100*(i+1))))
+	}
+
+	buf := &bytes.Buffer{}
+	k := &kmlWriter{w: errRememberer{w: nopCloser{buf}}}
+	if err := nmea.Process(strings.NewReader(strings.Join(lines, "\n")), k, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	for _, exp := range []string{
+		"<coordinates>35.500000,31.466667,100.0</coordinates>",
+		"<coordinates>35.500000,31.466667,200.0</coordinates>",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("Expected %q in\n%v", exp, buf)
+		}
+	}
+}
+
+func TestRenderSkipsVoid(t *testing.T) {
+	buf := &bytes.Buffer{}
+	k := &kmlWriter{w: errRememberer{w: nopCloser{buf}}}
+	if err := nmea.Process(strings.NewReader(
+		"$GPRMC,123520,V,0000.000,N,00000.000,E,0.0,0.0,230394,,*02\n"+
+			"$GPRMC,123519,A,3128.000,N,03530.000,E,0.0,0.0,230394,,*12\n"), k, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if n := strings.Count(buf.String(), "<coordinates>"); n != 1 {
+		t.Errorf("Expected only the valid fix rendered, got %v points in\n%v", n, buf)
+	}
+	if exp := "<coordinates>35.500000,31.466667,"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("Expected %q in\n%v", exp, buf)
+	}
+}
+
+func TestRenderSimplified(t *testing.T) {
+	defer func(s float64) { *simp = s }(*simp)
+	*simp = 10
+
+	// A minute heading due east along the equator, with a turn
+	// north halfway.
+	var lines []string
+	for i := 0; i < 60; i++ {
+		lat, lon := 0.0, 0.0001*float64(i)
+		if i > 30 {
+			lat, lon = 0.0001*float64(i-30), 0.003
+		}
+		lines = append(lines, nmea.AppendChecksum(fmt.Sprintf(
+			"$GPRMC,1200%02d,A,%09.4f,N,%010.4f,E,22.0,90.0,010120,,", i, lat*60, lon*60)))
+	}
+
+	buf := &bytes.Buffer{}
+	k := &kmlWriter{w: errRememberer{w: nopCloser{buf}}}
+	if err := nmea.Process(strings.NewReader(strings.Join(lines, "\n")), k, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	k.Close()
+	if got := strings.Count(buf.String(), "<Placemark>"); got != 3 {
+		t.Errorf("Expected the start, turn and end, got %v points:\n%v", got, buf)
+	}
+}
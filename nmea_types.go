@@ -5,6 +5,75 @@ import (
 	"time"
 )
 
+// A RawHandler receives every sentence that passes checksum
+// validation, before it's parsed.  This includes sentences of types
+// the package doesn't understand.
+type RawHandler interface {
+	HandleRaw(string)
+}
+
+// An EpochHandler is notified when a new fix cycle begins, before
+// the first sentence of the cycle is handled.  Cycles are marked by
+// a change in the time of an RMC or GGA sentence.  The time is that
+// of the sentence starting the cycle, so it has no date if that was
+// a GGA.
+type EpochHandler interface {
+	HandleEpoch(t time.Time)
+}
+
+// A Flusher is a handler holding state that's only delivered once
+// more input arrives, e.g. an incomplete GSV set.  Flush is called
+// when the input ends so the handler can deliver what it has.
+type Flusher interface {
+	Flush()
+}
+
+// Mode is the FAA mode indicator carried by many sentences from NMEA
+// 2.3 and later receivers.  The zero value means the sentence didn't
+// carry a mode.
+type Mode rune
+
+// Mode values.
+const (
+	Autonomous   Mode = 'A'
+	Differential Mode = 'D'
+	Estimated    Mode = 'E'
+	FloatRTK     Mode = 'F'
+	Manual       Mode = 'M'
+	NotValid     Mode = 'N'
+	Precise      Mode = 'P'
+	RTK          Mode = 'R'
+	Simulator    Mode = 'S'
+)
+
+var modeNames = map[Mode]string{
+	0:            "unspecified",
+	Autonomous:   "autonomous",
+	Differential: "differential",
+	Estimated:    "estimated",
+	FloatRTK:     "float rtk",
+	Manual:       "manual",
+	NotValid:     "not valid",
+	Precise:      "precise",
+	RTK:          "rtk",
+	Simulator:    "simulator",
+}
+
+// ParseMode returns the Mode for a mode indicator letter.
+func ParseMode(r rune) (Mode, error) {
+	if _, ok := modeNames[Mode(r)]; !ok || r == 0 {
+		return 0, &ParseError{"mode", string(r), "unknown mode indicator"}
+	}
+	return Mode(r), nil
+}
+
+func (m Mode) String() string {
+	if n, ok := modeNames[m]; ok {
+		return n
+	}
+	return fmt.Sprintf("[Invalid Mode: %q]", rune(m))
+}
+
 // FixQuality represents the quality of a position fix in a GGA packet.
 type FixQuality int
 
@@ -19,6 +88,9 @@ const (
 	EstimatedFix
 	ManualInputModeFix
 	SimulationModeFix
+	// SBASFix is a fix augmented by a satellite based system such
+	// as WAAS or EGNOS.
+	SBASFix
 )
 
 var fixNames = []string{
@@ -31,6 +103,7 @@ var fixNames = []string{
 	EstimatedFix:              "estimated",
 	ManualInputModeFix:        "manual mode",
 	SimulationModeFix:         "sim mode",
+	SBASFix:                   "sbas",
 }
 
 func (q FixQuality) String() string {
@@ -49,6 +122,28 @@ type GGA struct {
 	HorizontalDilution  float64
 	Altitude            float64
 	GeoidHeight         float64
+	DataID              int // See STN.
+	// ExtraFields holds any fields following those defined for
+	// the sentence, such as those of later NMEA versions or vendor
+	// extensions, or is nil.
+	ExtraFields []string
+}
+
+// EllipsoidalAltitude returns the height in meters above the WGS84
+// ellipsoid.
+//
+// Altitude is orthometric (above mean sea level, as approximated by
+// the geoid), and GeoidHeight is the height of the geoid above the
+// ellipsoid, so the ellipsoidal height is their sum.  GeoidHeight is
+// negative wherever the geoid lies below the ellipsoid, as it does
+// over much of North America.
+func (g GGA) EllipsoidalAltitude() float64 {
+	return g.Altitude + g.GeoidHeight
+}
+
+// Valid reports whether g reports a fix.
+func (g GGA) Valid() bool {
+	return g.Quality != InvalidFix
 }
 
 // A GGAHandler handles GGA messages from a stream.
@@ -61,6 +156,18 @@ type GLL struct {
 	Latitude, Longitude float64
 	Taken               time.Time
 	Active              bool
+	Mode                Mode
+	DataID              int // See STN.
+	// ExtraFields holds any fields following those defined for
+	// the sentence, such as those of later NMEA versions or vendor
+	// extensions, or is nil.
+	ExtraFields []string
+}
+
+// Valid reports whether g reports a fix: it's active, and its mode
+// (if any) isn't NotValid.
+func (g GLL) Valid() bool {
+	return g.Active && g.Mode != NotValid
 }
 
 // A GLLHandler handles GLL messages from a stream.
@@ -87,12 +194,55 @@ func (g GSAFix) String() string {
 	return []string{"", "no fix", "2D fix", "3D fix"}[g]
 }
 
+// HasFix reports whether g is a 2D or 3D fix.
+func (g GSAFix) HasFix() bool {
+	return g == Fix2D || g == Fix3D
+}
+
+// Has3D reports whether g is a 3D fix.
+func (g GSAFix) Has3D() bool {
+	return g == Fix3D
+}
+
 // GSA represents a Overall Satellite data message.
 type GSA struct {
 	Auto             bool
 	Fix              GSAFix
 	SatsUsed         []int
 	PDOP, HDOP, VDOP float64
+	// SystemID identifies the constellation of SatsUsed (1 for
+	// GPS, 2 for GLONASS, 3 for Galileo, 4 for BeiDou) for NMEA
+	// 4.10 and later receivers, or is 0.
+	SystemID int
+}
+
+// Usable reports whether g describes a fix with a PDOP no greater
+// than maxPDOP.  A fix without a PDOP isn't usable.
+func (g GSA) Usable(maxPDOP float64) bool {
+	return g.Fix.HasFix() && g.PDOP > 0 && g.PDOP <= maxPDOP
+}
+
+// systemConstellations maps GSA system IDs to constellations.
+var systemConstellations = map[int]Constellation{
+	1: GPSConstellation,
+	2: GLONASSConstellation,
+	3: GalileoConstellation,
+	4: BeiDouConstellation,
+}
+
+// SatIDs returns the identities of the satellites used, given the
+// talker ID of g.  Their constellation is given by SystemID if set,
+// and is otherwise found as by GSVSatInfo.Constellation.
+func (g GSA) SatIDs(talker string) []SatID {
+	ids := make([]SatID, 0, len(g.SatsUsed))
+	for _, prn := range g.SatsUsed {
+		c, ok := systemConstellations[g.SystemID]
+		if !ok {
+			c = GSVSatInfo{PRN: prn}.Constellation(talker)
+		}
+		ids = append(ids, SatID{c, prn})
+	}
+	return ids
 }
 
 // A GSAHandler handles GSA messages from a stream.
@@ -108,12 +258,102 @@ type GSVSatInfo struct {
 	SNR       int
 }
 
+// Constellation identifies a satellite navigation system.
+type Constellation int
+
+// Constellation values.
+const (
+	UnknownConstellation Constellation = iota
+	GPSConstellation
+	SBASConstellation
+	GLONASSConstellation
+	GalileoConstellation
+	BeiDouConstellation
+	QZSSConstellation
+)
+
+var constellationNames = []string{
+	UnknownConstellation: "unknown",
+	GPSConstellation:     "GPS",
+	SBASConstellation:    "SBAS",
+	GLONASSConstellation: "GLONASS",
+	GalileoConstellation: "Galileo",
+	BeiDouConstellation:  "BeiDou",
+	QZSSConstellation:    "QZSS",
+}
+
+func (c Constellation) String() string {
+	if c < 0 || int(c) >= len(constellationNames) {
+		return fmt.Sprintf("[Invalid Constellation: %d]", c)
+	}
+	return constellationNames[c]
+}
+
+// talkerConstellations maps the talker IDs dedicated to a single
+// system.  GP and GN sentences may describe satellites of any system.
+var talkerConstellations = map[string]Constellation{
+	"GL": GLONASSConstellation,
+	"GA": GalileoConstellation,
+	"GB": BeiDouConstellation,
+	"BD": BeiDouConstellation,
+	"GQ": QZSSConstellation,
+	"QZ": QZSSConstellation,
+}
+
+// Constellation returns the system the satellite belongs to, given
+// the talker ID (e.g. "GP") of the GSV message describing it.
+//
+// Single system talkers identify the system directly.  Otherwise
+// the system is inferred from the usual PRN ranges: 1-32 GPS, 33-64
+// and 120-158 SBAS, 65-96 GLONASS, 193-202 QZSS, 301-336 Galileo and
+// 401-437 BeiDou.
+func (s GSVSatInfo) Constellation(talker string) Constellation {
+	if c, ok := talkerConstellations[talker]; ok {
+		return c
+	}
+	switch p := s.PRN; {
+	case p >= 1 && p <= 32:
+		return GPSConstellation
+	case p >= 33 && p <= 64, p >= 120 && p <= 158:
+		return SBASConstellation
+	case p >= 65 && p <= 96:
+		return GLONASSConstellation
+	case p >= 193 && p <= 202:
+		return QZSSConstellation
+	case p >= 301 && p <= 336:
+		return GalileoConstellation
+	case p >= 401 && p <= 437:
+		return BeiDouConstellation
+	}
+	return UnknownConstellation
+}
+
+// A SatID identifies a satellite.  A PRN alone is ambiguous: under
+// NMEA 4.10, Galileo and BeiDou satellites are numbered from 1, as
+// GPS satellites are.
+type SatID struct {
+	Constellation Constellation
+	PRN           int
+}
+
+// ID returns the identity of the satellite, given the talker ID of
+// the GSV message describing it.
+func (s GSVSatInfo) ID(talker string) SatID {
+	return SatID{s.Constellation(talker), s.PRN}
+}
+
 // GSV represents a Detailed Satellite data message.
 type GSV struct {
 	InView         int
 	SentenceNum    int
 	TotalSentences int
 	SatInfo        []GSVSatInfo
+	// SignalID identifies the signal the satellites were tracked
+	// on, for NMEA 4.10 and later receivers, or is 0.
+	SignalID int
+	// Truncated is true if the sentence ended partway through a
+	// satellite's fields, which were dropped.
+	Truncated bool
 }
 
 // A GSVHandler handles GSV messages from a stream.
@@ -129,6 +369,18 @@ type RMC struct {
 	Speed               float64
 	Angle               float64
 	Magvar              float64
+	Mode                Mode
+	DataID              int // See STN.
+	// ExtraFields holds any fields following those defined for
+	// the sentence, such as those of later NMEA versions or vendor
+	// extensions, or is nil.
+	ExtraFields []string
+}
+
+// Valid reports whether r reports a fix, i.e. its status is 'A'
+// (active) rather than 'V' (void).
+func (r RMC) Valid() bool {
+	return r.Status == 'A'
 }
 
 // A RMCHandler handles RMC messages from a stream.
@@ -140,6 +392,16 @@ type RMCHandler interface {
 type VTG struct {
 	True, Magnetic float64
 	Knots, KMH     float64
+	Mode           Mode
+	DataID         int // See STN.
+	// Valid is false if the receiver had no velocity solution,
+	// leaving the speed fields empty or reporting a NotValid
+	// mode, in which case the zero speeds are meaningless.
+	Valid bool
+	// ExtraFields holds any fields following those defined for
+	// the sentence, such as those of later NMEA versions or vendor
+	// extensions, or is nil.
+	ExtraFields []string
 }
 
 // A VTGHandler handles VTG messages from a stream.
@@ -152,7 +414,534 @@ type ZDA struct {
 	Timestamp time.Time
 }
 
+// UTC returns the time the sentence described.  ZDA gives the time
+// of day in UTC, but Timestamp carries it as a wall clock in the
+// sentence's local zone, so this rebuilds it in UTC.
+func (z ZDA) UTC() time.Time {
+	t := z.Timestamp
+	return time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// Local returns the time the sentence described in its local zone.
+func (z ZDA) Local() time.Time {
+	return z.UTC().In(z.Timestamp.Location())
+}
+
+// String returns the local time followed by the UTC time in
+// parentheses, e.g. "2006-03-27T06:00:03-05:00 (2006-03-27T11:00:03Z)",
+// both in RFC 3339 format.  Times in UTC are only shown once.
+func (z ZDA) String() string {
+	utc := z.UTC().Format(time.RFC3339Nano)
+	if _, offset := z.Timestamp.Zone(); offset == 0 {
+		return utc
+	}
+	return z.Local().Format(time.RFC3339Nano) + " (" + utc + ")"
+}
+
 // A ZDAHandler handles ZDA messages from a stream.
 type ZDAHandler interface {
 	HandleZDA(ZDA)
 }
+
+// RTE represents a route message.
+//
+// Long routes span several sentences; see RouteBuilder for combining
+// them.
+type RTE struct {
+	TotalSentences int
+	SentenceNum    int
+	// Complete is true if the sentences list the complete route,
+	// and false if they list only the working route (the remaining
+	// waypoints).
+	Complete  bool
+	RouteID   string
+	Waypoints []string
+}
+
+// A RTEHandler handles RTE messages from a stream.
+type RTEHandler interface {
+	HandleRTE(RTE)
+}
+
+// WPL represents a Waypoint Location information message.
+type WPL struct {
+	Latitude, Longitude float64
+	Name                string
+}
+
+// A WPLHandler handles WPL messages from a stream.
+type WPLHandler interface {
+	HandleWPL(WPL)
+}
+
+// XDRMeasurement is a single reading from a transducer.
+type XDRMeasurement struct {
+	// Type is the kind of transducer (e.g. 'C' for temperature,
+	// 'P' for pressure, 'H' for humidity).
+	Type  rune
+	Value float64
+	// Unit is the unit of Value (e.g. 'C' for Celsius, 'B' for bars).
+	Unit rune
+	Name string
+}
+
+// XDR represents a Transducer Measurement message.
+type XDR struct {
+	Measurements []XDRMeasurement
+	DataID       int // See STN.
+}
+
+// A XDRHandler handles XDR messages from a stream.
+type XDRHandler interface {
+	HandleXDR(XDR)
+}
+
+// Wind is a wind speed and direction relative to the vessel's bow.
+type Wind struct {
+	Angle float64
+	// Side is 'L' if the wind comes from port, or 'R' if from
+	// starboard.
+	Side            rune
+	Knots, MPS, KMH float64
+}
+
+// VWR represents a Relative Wind Speed and Angle message.
+type VWR struct {
+	Wind
+	DataID int // See STN.
+}
+
+// A VWRHandler handles VWR messages from a stream.
+type VWRHandler interface {
+	HandleVWR(VWR)
+}
+
+// VWT represents a True Wind Speed and Angle message.
+type VWT struct {
+	Wind
+	DataID int // See STN.
+}
+
+// A VWTHandler handles VWT messages from a stream.
+type VWTHandler interface {
+	HandleVWT(VWT)
+}
+
+// VPW represents a Speed Parallel to Wind message: the speed made
+// good towards the wind, negative when running downwind.
+type VPW struct {
+	Knots, KMH float64
+	DataID     int // See STN.
+}
+
+// A VPWHandler handles VPW messages from a stream.
+type VPWHandler interface {
+	HandleVPW(VPW)
+}
+
+// Depth is a water depth in several units.
+type Depth struct {
+	Feet, Meters, Fathoms float64
+}
+
+// DBT represents a Depth Below Transducer message.
+type DBT struct {
+	Depth
+	DataID int // See STN.
+}
+
+// A DBTHandler handles DBT messages from a stream.
+type DBTHandler interface {
+	HandleDBT(DBT)
+}
+
+// DBK represents a Depth Below Keel message.
+type DBK struct {
+	Depth
+	DataID int // See STN.
+}
+
+// A DBKHandler handles DBK messages from a stream.
+type DBKHandler interface {
+	HandleDBK(DBK)
+}
+
+// DBS represents a Depth Below Surface message.
+type DBS struct {
+	Depth
+	DataID int // See STN.
+}
+
+// A DBSHandler handles DBS messages from a stream.
+type DBSHandler interface {
+	HandleDBS(DBS)
+}
+
+// THS represents a True Heading and Status message.
+type THS struct {
+	Heading float64
+	// Status is 'A' (autonomous), 'E' (estimated), 'M' (manual
+	// input), 'S' (simulator) or 'V' (not valid).
+	Status rune
+	DataID int // See STN.
+}
+
+// A THSHandler handles THS messages from a stream.
+type THSHandler interface {
+	HandleTHS(THS)
+}
+
+// CrossTrack is the distance off the course to a waypoint.
+type CrossTrack struct {
+	Magnitude float64
+	// SteerDirection is 'L' or 'R', the way to steer to regain
+	// the course.
+	SteerDirection rune
+	// Unit is 'N' for nautical miles or 'K' for kilometers.
+	Unit rune
+}
+
+// XTE represents a Cross-Track Error, Measured message.
+type XTE struct {
+	// Valid is false when the receiver raises a general warning.
+	Valid bool
+	// CycleLock is false when a Loran-C receiver warns of a lost
+	// cycle lock.  Other receivers always report it as true.
+	CycleLock bool
+	CrossTrack
+	Mode   Mode
+	DataID int // See STN.
+}
+
+// A XTEHandler handles XTE messages from a stream.
+type XTEHandler interface {
+	HandleXTE(XTE)
+}
+
+// XTC represents a Cross-Track Error message, as computed by dead
+// reckoning.
+type XTC struct {
+	CrossTrack
+	DataID int // See STN.
+}
+
+// A XTCHandler handles XTC messages from a stream.
+type XTCHandler interface {
+	HandleXTC(XTC)
+}
+
+// WCV represents a Waypoint Closure Velocity message.
+type WCV struct {
+	// Velocity is the velocity made good towards the waypoint.
+	Velocity float64
+	// Unit is the unit of Velocity, always 'N' for knots.
+	Unit       rune
+	WaypointID string
+	Mode       Mode
+	DataID     int // See STN.
+}
+
+// A WCVHandler handles WCV messages from a stream.
+type WCVHandler interface {
+	HandleWCV(WCV)
+}
+
+// STN represents a Multiple Data ID message.
+//
+// Vessels with several identical sensors precede each sensor's
+// sentences with an STN identifying it.  The ID is also recorded in
+// the DataID of the sensor sentences following it in the same fix
+// cycle: positions, motion, heading, transducers, depth, wind,
+// steering, targets, beacon status and GST.  Sentences describing
+// the receiver or the voyage as a whole (GSA, GSV, ZDA, RTE, WPL,
+// MSK and the proprietary sentences) have no DataID.
+type STN struct {
+	DataID int
+}
+
+// A STNHandler handles STN messages from a stream.
+type STNHandler interface {
+	HandleSTN(STN)
+}
+
+// MSS represents a Beacon Receiver Status message from a DGPS beacon
+// receiver.
+type MSS struct {
+	// SignalStrength is in dB re 1 uV/m.
+	SignalStrength float64
+	// SNR is in dB.
+	SNR float64
+	// Frequency is the beacon frequency in kHz.
+	Frequency float64
+	// BitRate is the beacon bit rate in bits per second.
+	BitRate int
+	// Channel is the receiver channel, or 0 if not reported.
+	Channel int
+	DataID  int // See STN.
+}
+
+// A MSSHandler handles MSS messages from a stream.
+type MSSHandler interface {
+	HandleMSS(MSS)
+}
+
+// MSK represents a Beacon Receiver Control message, tuning a DGPS
+// beacon receiver.
+type MSK struct {
+	// Frequency is the beacon frequency in kHz.
+	Frequency     float64
+	FrequencyAuto bool
+	// BitRate is the beacon bit rate in bits per second.
+	BitRate     int
+	BitRateAuto bool
+	// StatusInterval is the interval in seconds between MSS
+	// sentences, or 0 for none.
+	StatusInterval int
+}
+
+// A MSKHandler handles MSK messages from a stream.
+type MSKHandler interface {
+	HandleMSK(MSK)
+}
+
+// VDR represents a Set and Drift message, describing the current
+// acting on the vessel.
+type VDR struct {
+	// DirectionTrue and DirectionMagnetic are the direction
+	// the current flows towards, in degrees.
+	DirectionTrue     float64
+	DirectionMagnetic float64
+	SpeedKnots        float64
+	DataID            int // See STN.
+}
+
+// A VDRHandler handles VDR messages from a stream.
+type VDRHandler interface {
+	HandleVDR(VDR)
+}
+
+// RSA represents a Rudder Sensor Angle message.
+//
+// Angles are in degrees, negative to port.  Vessels with a single
+// rudder report only the starboard (or single) sensor.
+type RSA struct {
+	Starboard      float64
+	StarboardValid bool
+	Port           float64
+	PortValid      bool
+	DataID         int // See STN.
+}
+
+// A RSAHandler handles RSA messages from a stream.
+type RSAHandler interface {
+	HandleRSA(RSA)
+}
+
+// Autopilot is the steering information common to the APA and APB
+// autopilot sentences.
+type Autopilot struct {
+	// Valid is false when the receiver raises a general warning.
+	Valid bool
+	// CycleLock is false when a Loran-C receiver warns of a lost
+	// cycle lock.
+	CycleLock bool
+	CrossTrack
+	// ArrivalCircle is true once the vessel is within the
+	// destination's arrival circle.
+	ArrivalCircle bool
+	// Perpendicular is true once the vessel has passed the
+	// perpendicular at the destination.
+	Perpendicular bool
+	// Bearing is from the origin to the destination.
+	Bearing float64
+	// BearingRef is 'T' if Bearing is true, 'M' if magnetic.
+	BearingRef    rune
+	DestinationID string
+}
+
+// APA represents an Autopilot Sentence "A" message, a legacy
+// predecessor of APB.
+type APA struct {
+	Autopilot
+	DataID int // See STN.
+}
+
+// An APAHandler handles APA messages from a stream.
+type APAHandler interface {
+	HandleAPA(APA)
+}
+
+// TTM represents a Tracked Target Message from a radar or ARPA unit.
+type TTM struct {
+	Number   int
+	Distance float64
+	Bearing  float64
+	// BearingRef is 'T' if Bearing is true, 'R' if relative to
+	// the vessel's heading.
+	BearingRef rune
+	Speed      float64
+	Course     float64
+	// CourseRef is 'T' if Course is true, 'R' if relative.
+	CourseRef rune
+	// CPA is the distance at the closest point of approach.
+	CPA float64
+	// TCPA is the time in minutes to the closest point of
+	// approach, negative once it has passed.
+	TCPA float64
+	// Unit is the unit of Distance, Speed and CPA: 'N' for
+	// nautical miles and knots, 'K' for kilometers and km/h, or
+	// 'S' for statute miles and mph.
+	Unit rune
+	Name string
+	// Status is 'L' for a lost target, 'Q' while acquiring, or
+	// 'T' while tracking.
+	Status rune
+	// Reference is true for a reference target, used to compute
+	// the vessel's speed over ground.
+	Reference bool
+	// Taken has no date, and is zero if the unit didn't send it.
+	Taken time.Time
+	// Acquisition is 'A' for automatic, 'M' for manual or 'R' for
+	// reported, or 0 if not sent.
+	Acquisition rune
+	DataID      int // See STN.
+}
+
+// A TTMHandler handles TTM messages from a stream.
+type TTMHandler interface {
+	HandleTTM(TTM)
+}
+
+// TLL represents a Target Latitude and Longitude message from a
+// radar or ARPA unit.
+type TLL struct {
+	Number              int
+	Latitude, Longitude float64
+	Name                string
+	// Taken has no date, and is zero if the unit didn't send it.
+	Taken time.Time
+	// Status is as for TTM.
+	Status    rune
+	Reference bool
+	DataID    int // See STN.
+}
+
+// A TLLHandler handles TLL messages from a stream.
+type TLLHandler interface {
+	HandleTLL(TLL)
+}
+
+// GST represents a Pseudorange Noise Statistics message, estimating
+// the accuracy of the fix.  Errors are standard deviations in meters.
+type GST struct {
+	// Taken is dated from the last RMC or ZDA, if any preceded
+	// it, and otherwise on January 1st, year 0.
+	Taken time.Time
+	// RMS is the RMS value of the standard deviation of the
+	// range inputs to the navigation process.
+	RMS float64
+	// SemiMajor and SemiMinor are the axes of the error ellipse.
+	SemiMajor, SemiMinor float64
+	// Orientation is the direction of the error ellipse's semi-major
+	// axis in degrees from true north.
+	Orientation float64
+	LatError    float64
+	LonError    float64
+	AltError    float64
+	DataID      int // See STN.
+}
+
+// A GSTHandler handles GST messages from a stream.
+type GSTHandler interface {
+	HandleGST(GST)
+}
+
+// PUBXNavStat is the navigation status reported by a u-blox PUBX,00
+// message.
+type PUBXNavStat int
+
+// PUBXNavStat values.
+const (
+	PUBXUnknown PUBXNavStat = iota
+	PUBXNoFix
+	PUBXDeadReckoning
+	PUBX2D
+	PUBX3D
+	PUBXDifferential2D
+	PUBXDifferential3D
+	PUBXCombined
+	PUBXTimeOnly
+)
+
+var pubxNavStatCodes = map[string]PUBXNavStat{
+	"NF": PUBXNoFix,
+	"DR": PUBXDeadReckoning,
+	"G2": PUBX2D,
+	"G3": PUBX3D,
+	"D2": PUBXDifferential2D,
+	"D3": PUBXDifferential3D,
+	"RK": PUBXCombined,
+	"TT": PUBXTimeOnly,
+}
+
+var pubxNavStatNames = []string{
+	PUBXUnknown:        "unknown",
+	PUBXNoFix:          "no fix",
+	PUBXDeadReckoning:  "dead reckoning",
+	PUBX2D:             "2D fix",
+	PUBX3D:             "3D fix",
+	PUBXDifferential2D: "differential 2D fix",
+	PUBXDifferential3D: "differential 3D fix",
+	PUBXCombined:       "gps + dead reckoning",
+	PUBXTimeOnly:       "time only",
+}
+
+func (n PUBXNavStat) String() string {
+	if n < 0 || int(n) >= len(pubxNavStatNames) {
+		return fmt.Sprintf("[Invalid PUBX NavStat: %d]", n)
+	}
+	return pubxNavStatNames[n]
+}
+
+// PUBX00 represents a u-blox proprietary position message.
+type PUBX00 struct {
+	Taken               time.Time
+	Latitude, Longitude float64
+	// Altitude is the height in meters above the user datum
+	// ellipsoid.
+	Altitude float64
+	NavStat  PUBXNavStat
+	// HorizontalAccuracy and VerticalAccuracy are accuracy
+	// estimates in meters.
+	HorizontalAccuracy, VerticalAccuracy float64
+	// SpeedKMH is the speed over ground in km/h.
+	SpeedKMH float64
+	// Course is the course over ground in degrees.
+	Course float64
+	// VerticalVelocity is in m/s, positive downwards.
+	VerticalVelocity float64
+	// DiffAge is the age of differential corrections in seconds,
+	// or zero if none are in use.
+	DiffAge          float64
+	HDOP, VDOP, TDOP float64
+	NumSats          int
+}
+
+// A PUBX00Handler handles PUBX00 messages from a stream.
+type PUBX00Handler interface {
+	HandlePUBX00(PUBX00)
+}
+
+// PGRME represents a Garmin proprietary estimated error message.
+// All values are in meters.
+type PGRME struct {
+	HorizontalError float64
+	VerticalError   float64
+	SphericalError  float64
+}
+
+// A PGRMEHandler handles PGRME messages from a stream.
+type PGRMEHandler interface {
+	HandlePGRME(PGRME)
+}
@@ -0,0 +1,89 @@
+// nmeafix repairs the checksums of NMEA logs, so they can be read by
+// tools that insist on valid checksums.
+//
+// Each sentence with a missing or incorrect checksum is rewritten
+// with the correct one.  Other lines are copied unchanged.  Note that
+// this makes corrupt sentences look valid; only use it on logs whose
+// checksums were damaged separately from their contents.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/dustin/go-nmea"
+)
+
+var verbose = flag.Bool("v", false, "report altered lines on stderr")
+
+// fixLine returns line with a correct checksum, and whether that
+// required changing it.  Lines that don't look like sentences are
+// returned unchanged.
+func fixLine(line string) (string, bool) {
+	if nmea.Valid(line) || len(line) < 2 || (line[0] != '$' && line[0] != '!') {
+		return line, false
+	}
+	payload := line
+	if i := strings.IndexByte(line, '*'); i >= 0 {
+		payload = line[:i]
+	}
+	if strings.IndexByte(payload, ',') < 2 {
+		return line, false
+	}
+	for i := 1; i < len(payload); i++ {
+		if c := payload[i]; c < ' ' || c > '~' || c == '$' || c == '!' {
+			return line, false
+		}
+	}
+	return nmea.AppendChecksum(payload), true
+}
+
+// fix copies r to w, fixing checksums, and reporting each altered
+// line to report if it's not nil.  It returns the number of lines
+// altered.
+func fix(r io.Reader, w io.Writer, report func(n int, was, is string)) (int, error) {
+	s := bufio.NewScanner(r)
+	bw := bufio.NewWriter(w)
+	n, altered := 0, 0
+	for s.Scan() {
+		n++
+		line, changed := fixLine(s.Text())
+		if changed {
+			altered++
+			if report != nil {
+				report(n, s.Text(), line)
+			}
+		}
+		if _, err := bw.WriteString(line + "\r\n"); err != nil {
+			return altered, err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return altered, err
+	}
+	return altered, bw.Flush()
+}
+
+func main() {
+	flag.Parse()
+
+	var report func(int, string, string)
+	if *verbose {
+		report = func(n int, was, is string) {
+			fmt.Fprintf(os.Stderr, "%d: %s -> %s\n", n, was, is)
+		}
+	}
+
+	altered, err := fix(os.Stdin, os.Stdout, report)
+	if err != nil {
+		log.Fatalf("Error fixing checksums: %v", err)
+	}
+	if *verbose {
+		log.Printf("Altered %d lines", altered)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFixLine(t *testing.T) {
+	tests := []struct {
+		in, exp string
+		changed bool
+	}{
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", false},
+		{"$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7d", "$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7d", false},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*11", "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", true},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K", "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", true},
+		{"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*4", "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48", true},
+		{"!AIVDM,1,1,,A,13aG?P0P00PD;88MD5MTDww@2<0L,0", "!AIVDM,1,1,,A,13aG?P0P00PD;88MD5MTDww@2<0L,0*71", true},
+		{"# a comment", "# a comment", false},
+		{"", "", false},
+		{"$GPVTG", "$GPVTG", false},
+		{"$GPVTG,0\x01", "$GPVTG,0\x01", false},
+	}
+	for _, test := range tests {
+		got, changed := fixLine(test.in)
+		if got != test.exp || changed != test.changed {
+			t.Errorf("fixLine(%q) = %q, %v; want %q, %v", test.in, got, changed, test.exp, test.changed)
+		}
+	}
+}
+
+func TestFix(t *testing.T) {
+	in := "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48\n" +
+		"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*11\n"
+	var reported []int
+	out := &bytes.Buffer{}
+	n, err := fix(strings.NewReader(in), out, func(n int, was, is string) {
+		reported = append(reported, n)
+	})
+	if err != nil {
+		t.Fatalf("Error fixing: %v", err)
+	}
+	if n != 1 || len(reported) != 1 || reported[0] != 2 {
+		t.Errorf("Expected line 2 alone to be altered, got %v (%v)", n, reported)
+	}
+	exp := "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48\r\n" +
+		"$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48\r\n"
+	if out.String() != exp {
+		t.Errorf("Expected\n%q\ngot\n%q", exp, out)
+	}
+}
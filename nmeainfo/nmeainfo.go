@@ -0,0 +1,156 @@
+// nmeainfo summarizes NMEA logs: the sentences and talkers they
+// contain, the time and area they cover, and how much of them is
+// corrupt.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-nmea"
+)
+
+type summary struct {
+	types   map[string]int
+	talkers map[string]int
+
+	start, end time.Time
+
+	havePos        bool
+	minLat, maxLat float64
+	minLon, maxLon float64
+
+	stats nmea.Stats
+}
+
+func newSummary() *summary {
+	return &summary{
+		types:   map[string]int{},
+		talkers: map[string]int{},
+		minLat:  math.Inf(1), maxLat: math.Inf(-1),
+		minLon: math.Inf(1), maxLon: math.Inf(-1),
+	}
+}
+
+func (s *summary) HandleRaw(line string) {
+	addr := line[1:]
+	if i := strings.IndexAny(addr, ",*"); i >= 0 {
+		addr = addr[:i]
+	}
+	switch {
+	case strings.HasPrefix(addr, "P"):
+		s.types[addr]++
+	case len(addr) == 5:
+		s.talkers[addr[:2]]++
+		s.types[addr[2:]]++
+	}
+}
+
+func (s *summary) seen(t time.Time) {
+	if s.start.IsZero() || t.Before(s.start) {
+		s.start = t
+	}
+	if t.After(s.end) {
+		s.end = t
+	}
+}
+
+func (s *summary) position(lat, lon float64) {
+	s.havePos = true
+	s.minLat = math.Min(s.minLat, lat)
+	s.maxLat = math.Max(s.maxLat, lat)
+	s.minLon = math.Min(s.minLon, lon)
+	s.maxLon = math.Max(s.maxLon, lon)
+}
+
+func (s *summary) HandleRMC(m nmea.RMC) {
+	s.seen(m.Timestamp)
+	if m.Valid() {
+		s.position(m.Latitude, m.Longitude)
+	}
+}
+
+func (s *summary) HandleGGA(m nmea.GGA) {
+	if m.Valid() {
+		s.position(m.Latitude, m.Longitude)
+	}
+}
+
+func (s *summary) HandleZDA(m nmea.ZDA) {
+	s.seen(m.Timestamp)
+}
+
+func summarize(r io.Reader) (*summary, error) {
+	s := newSummary()
+	p := &nmea.Processor{}
+	err := p.Process(r, s, func(string, error) error { return nil })
+	s.stats = p.Stats()
+	return s, err
+}
+
+func sortedCounts(m map[string]int) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var rv []string
+	for _, k := range keys {
+		rv = append(rv, fmt.Sprintf("%v=%v", k, m[k]))
+	}
+	return rv
+}
+
+func (s *summary) write(w io.Writer) {
+	fmt.Fprintf(w, "sentences: %v\n", strings.Join(sortedCounts(s.types), " "))
+	fmt.Fprintf(w, "talkers:   %v\n", strings.Join(sortedCounts(s.talkers), " "))
+	if !s.start.IsZero() {
+		fmt.Fprintf(w, "time:      %v - %v (%v)\n", s.start.Format(time.RFC3339),
+			s.end.Format(time.RFC3339), s.end.Sub(s.start))
+	}
+	if s.havePos {
+		fmt.Fprintf(w, "area:      %.6f,%.6f - %.6f,%.6f\n", s.minLat, s.minLon, s.maxLat, s.maxLon)
+	}
+	bad := 0.0
+	if s.stats.Sentences > 0 {
+		bad = 100 * float64(s.stats.BadChecksum) / float64(s.stats.Sentences)
+	}
+	fmt.Fprintf(w, "checksum:  %v of %v lines failed (%.1f%%)\n",
+		s.stats.BadChecksum, s.stats.Sentences, bad)
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		s, err := summarize(os.Stdin)
+		if err != nil {
+			log.Fatalf("Error reading stdin: %v", err)
+		}
+		s.write(os.Stdout)
+		return
+	}
+
+	for _, fn := range flag.Args() {
+		f, err := os.Open(fn)
+		if err != nil {
+			log.Fatalf("Error opening %v: %v", fn, err)
+		}
+		s, err := summarize(f)
+		f.Close()
+		if err != nil {
+			log.Fatalf("Error reading %v: %v", fn, err)
+		}
+		if flag.NArg() > 1 {
+			fmt.Printf("%v:\n", fn)
+		}
+		s.write(os.Stdout)
+	}
+}
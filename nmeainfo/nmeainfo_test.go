@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ubloxSample = `$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74
+$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F
+$GPGGA,162254.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*65
+$GPGSA,A,2,25,01,22,,,,,,,,,,2.56,2.36,1.00*02
+$GPGSV,4,1,14,25,15,175,30,14,80,041,,19,38,259,14,01,52,223,18*76
+$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7D
+$GPGSV,4,3,14,15,27,134,18,03,25,222,,22,51,057,16,09,07,036,*79
+$GPGSV,4,4,14,07,01,181,,15,25,135,*76
+$GPGLL,3723.02837,N,12159.39853,W,162254.00,A,A*7C
+$GPZDA,162254.00,11,07,2006,00,00*63
+$GPRMC,162354.00,A,3723.52837,N,12159.09853,W,0.820,188.36,110706,,,A*73
+$GPRMC,162354.00,A,3723.52837,N,12159.09853,W,0.820,188.36,110706,,,A*00
+`
+
+func TestSummarize(t *testing.T) {
+	s, err := summarize(strings.NewReader(ubloxSample))
+	if err != nil {
+		t.Fatalf("Error summarizing: %v", err)
+	}
+
+	expTypes := map[string]int{
+		"RMC": 2, "VTG": 1, "GGA": 1, "GSA": 1, "GSV": 4, "GLL": 1, "ZDA": 1,
+	}
+	if !reflect.DeepEqual(s.types, expTypes) {
+		t.Errorf("Expected types %v, got %v", expTypes, s.types)
+	}
+	if !reflect.DeepEqual(s.talkers, map[string]int{"GP": 11}) {
+		t.Errorf("Expected only GP talkers, got %v", s.talkers)
+	}
+
+	start := time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC)
+	if !s.start.Equal(start) || s.end.Sub(s.start) != time.Minute {
+		t.Errorf("Expected a minute from %v, got %v - %v", start, s.start, s.end)
+	}
+
+	out := &strings.Builder{}
+	s.write(out)
+	for _, exp := range []string{
+		"sentences: GGA=1 GLL=1 GSA=1 GSV=4 RMC=2 VTG=1 ZDA=1\n",
+		"(1m0s)\n",
+		"area:      37.383806,-121.989975 - 37.392139,-121.984976\n",
+		"checksum:  1 of 12 lines failed (8.3%)\n",
+	} {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected %q in output:\n%v", exp, out)
+		}
+	}
+}
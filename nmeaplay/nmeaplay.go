@@ -0,0 +1,109 @@
+// nmeaplay replays a recorded NMEA log, paced by its RMC timestamps,
+// for testing applications without a receiver.
+//
+// The log is written to stdout, or to the file given with -o, which
+// may be a serial device or the slave side of a pseudo-terminal.
+// Each RMC is held back until the time since the previous one has
+// passed, scaled by -speed; other lines follow without delay.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-nmea"
+)
+
+var (
+	speed  = flag.Float64("speed", 1, "replay speed multiplier (0 for as fast as possible)")
+	loop   = flag.Bool("loop", false, "replay the log forever")
+	output = flag.String("o", "", "write to this file or device instead of stdout")
+)
+
+// player writes lines paced by their RMC timestamps.
+type player struct {
+	speed float64
+	sleep func(time.Duration)
+}
+
+// play writes lines to w with CRLF terminators.  The first RMC is
+// written immediately, as is any RMC not following the previous one.
+func (p *player) play(w io.Writer, lines []string) error {
+	var prev time.Time
+	for _, line := range lines {
+		if t, ok := rmcTime(line); ok {
+			if d := t.Sub(prev); !prev.IsZero() && d > 0 && p.speed > 0 {
+				p.sleep(time.Duration(float64(d) / p.speed))
+			}
+			prev = t
+		}
+		if _, err := io.WriteString(w, line+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rmcTime returns the timestamp of line if it's a dated RMC.
+func rmcTime(line string) (time.Time, bool) {
+	m, err := nmea.ParseLine(line)
+	if err != nil {
+		return time.Time{}, false
+	}
+	r, ok := m.(nmea.RMC)
+	return r.Timestamp, ok && r.Timestamp.Year() > 0
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, s.Err()
+}
+
+func main() {
+	flag.Parse()
+
+	in := os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatalf("Error opening %v: %v", flag.Arg(0), err)
+		}
+		defer f.Close()
+		in = f
+	}
+	lines, err := readLines(in)
+	if err != nil {
+		log.Fatalf("Error reading log: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			log.Fatalf("Error opening %v: %v", *output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	p := &player{speed: *speed, sleep: time.Sleep}
+	for {
+		if err := p.play(out, lines); err != nil {
+			log.Fatalf("Error writing: %v", err)
+		}
+		if !*loop {
+			break
+		}
+	}
+}
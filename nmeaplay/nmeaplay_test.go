@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sample = []string{
+	"$GPRMC,120000.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*71",
+	"$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F",
+	"$GPRMC,120001.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*70",
+	"$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F",
+	"$GPRMC,120003.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72",
+}
+
+func TestPlayPacing(t *testing.T) {
+	tests := []struct {
+		speed float64
+		exp   []time.Duration
+	}{
+		{1, []time.Duration{time.Second, 2 * time.Second}},
+		{10, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}},
+		{0, nil},
+	}
+	for _, test := range tests {
+		var slept []time.Duration
+		p := &player{speed: test.speed, sleep: func(d time.Duration) { slept = append(slept, d) }}
+		buf := &bytes.Buffer{}
+		if err := p.play(buf, sample); err != nil {
+			t.Fatalf("Error playing: %v", err)
+		}
+		if !reflect.DeepEqual(slept, test.exp) {
+			t.Errorf("At speed %v, expected pauses %v, got %v", test.speed, test.exp, slept)
+		}
+		if exp := strings.Join(sample, "\r\n") + "\r\n"; buf.String() != exp {
+			t.Errorf("Expected\n%q\ngot\n%q", exp, buf)
+		}
+	}
+}
+
+func TestPlayRealtime(t *testing.T) {
+	p := &player{speed: 10, sleep: time.Sleep}
+	start := time.Now()
+	if err := p.play(&bytes.Buffer{}, sample); err != nil {
+		t.Fatalf("Error playing: %v", err)
+	}
+	if d := time.Since(start); d < 300*time.Millisecond || d > 2*time.Second {
+		t.Errorf("Expected the 3s sample to take about 300ms at 10x, took %v", d)
+	}
+}
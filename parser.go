@@ -1,7 +1,6 @@
 package nmea
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
@@ -10,14 +9,37 @@ import (
 	"time"
 )
 
+// MaxSentenceLength is the maximum length of a standard sentence,
+// including the leading $ and the CRLF terminator.
+const MaxSentenceLength = 82
+
 var (
 	// ErrUnhandled is passed to the error handler for any message type unknown to this parser.
 	ErrUnhandled = errors.New("unhandled message type")
 
+	// ErrTooLong is passed to the error handler for lines exceeding
+	// a Processor's MaxLength.
+	ErrTooLong = errors.New("sentence too long")
+
+	// ErrEarlyTime is passed to the error handler for sentences
+	// rejected by a Processor's RejectEarly option.
+	ErrEarlyTime = errors.New("timestamp before earliest valid time")
+
+	// ErrTruncated is returned by Process when the input ended
+	// partway through a sentence: its final line had no line
+	// terminator and failed checksum validation.  The checksum
+	// error is also passed to the error handler, wrapped with
+	// ErrTruncated.
+	ErrTruncated = errors.New("input ended mid-sentence")
+
+	// ErrStopped is returned by a Processor whose StopWhen
+	// condition was met.
+	ErrStopped = errors.New("processing stopped")
+
 	errBadChecksum = errors.New("bad checksum")
 	errShortMsg    = errors.New("short message")
 
-	parsers = map[string]func([]string, interface{}) error{
+	parsers = map[string]func(*Processor, []string, interface{}) error{
 		"RMC": rmcParser,
 		"VTG": vtgParser,
 		"GGA": ggaParser,
@@ -25,11 +47,51 @@ var (
 		"GLL": gllParser,
 		"ZDA": zdaParser,
 		"GSV": gsvParser,
+		"RTE": rteParser,
+		"WPL": wplParser,
+		"XDR": xdrParser,
+		"VWR": vwrParser,
+		"VWT": vwtParser,
+		"DBT": dbtParser,
+		"DBK": dbkParser,
+		"DBS": dbsParser,
+		"THS": thsParser,
+		"VDM": vdmParser,
+		"VDO": vdmParser,
+		"XTE": xteParser,
+		"XTC": xtcParser,
+		"WCV": wcvParser,
+		"STN": stnParser,
+		"MSS": mssParser,
+		"MSK": mskParser,
+		"VDR": vdrParser,
+		"RSA": rsaParser,
+		"APA": apaParser,
+		"TTM": ttmParser,
+		"TLL": tllParser,
+		"GST": gstParser,
+		"VPW": vpwParser,
+
+		"PGRME": pgrmeParser,
+		"PUBX":  pubxParser,
 	}
 )
 
+// ParseError describes a field whose value was well-formed but not
+// meaningful, e.g. a latitude beyond the poles.
+type ParseError struct {
+	Field string
+	Value string
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %v %q: %v", e.Field, e.Value, e.Msg)
+}
+
 type cumulativeErrorParser struct {
-	err error
+	err    error
+	strict bool
 }
 
 func (c *cumulativeErrorParser) parseFloat(s string) float64 {
@@ -54,34 +116,142 @@ func (c *cumulativeErrorParser) parseInt(s string) int {
 	return int(rv)
 }
 
+// parseUnit parses a value followed by a unit marker, which must be
+// the expected one when the value is present.
+func (c *cumulativeErrorParser) parseUnit(s, unit, exp string) float64 {
+	if s != "" && unit != exp && c.err == nil {
+		c.err = &ParseError{"unit", unit, "expected " + exp}
+	}
+	return c.parseFloat(s)
+}
+
+// parseMode parses a mode indicator field.  Unknown indicators are
+// kept as is, but are an error in strict mode.
+func (c *cumulativeErrorParser) parseMode(s string) Mode {
+	if s == "" || c.err != nil {
+		return 0
+	}
+	m, err := ParseMode(rune(s[0]))
+	if err != nil {
+		if c.strict {
+			c.err = err
+		}
+		return Mode(s[0])
+	}
+	return m
+}
+
 func (c *cumulativeErrorParser) parseDMS(s, ref string) float64 {
-	if c.err != nil {
+	// Receivers without a fix leave both fields empty.
+	if c.err != nil || (s == "" && ref == "") {
 		return 0
 	}
 	n := 2
 	m := 1.0
+	field, limit := "latitude", 90.0
 	switch ref {
 	case "E":
 		n = 3
+		field, limit = "longitude", 180
 	case "W":
 		n = 3
 		m = -1
+		field, limit = "longitude", 180
 	case "S":
 		m = -1
 	case "N":
 	default:
-		c.err = errors.New("direction must be one of NESW")
+		c.err = &ParseError{"hemisphere", ref, "must be one of NESW"}
 		return 0
 	}
 
+	if len(s) < n {
+		c.err = &ParseError{field, s + "," + ref, "too short"}
+		return 0
+	}
+	// A digit lost from the degrees or minutes shifts the split
+	// between them, which only the width of the whole part shows.
+	if c.strict {
+		whole := s
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			whole = s[:i]
+		}
+		if len(whole) != n+2 {
+			c.err = &ParseError{field, s + "," + ref, "wrong number of digits"}
+			return 0
+		}
+	}
+
 	deg := c.parseFloat(s[:n])
 	min := c.parseFloat(s[n:])
+	if c.err == nil && (min < 0 || min >= 60) {
+		c.err = &ParseError{field, s + "," + ref, "minutes out of range"}
+		return 0
+	}
 	deg += (min / 60.0)
 	deg *= m
 
+	if c.strict && c.err == nil && (deg < -limit || deg > limit) {
+		c.err = &ParseError{field, s + "," + ref, "out of range"}
+		return 0
+	}
+
 	return deg
 }
 
+// parseTimeOfDay parses an optional hhmmss.ss time, returning the
+// zero time if it's absent.
+func (c *cumulativeErrorParser) parseTimeOfDay(s string) time.Time {
+	if s == "" || c.err != nil {
+		return time.Time{}
+	}
+	t, err := parseTimeOfDay(s)
+	if err != nil {
+		c.err = err
+	}
+	return t
+}
+
+// parseTargetStatus parses the status of a radar target.
+func (c *cumulativeErrorParser) parseTargetStatus(s string) rune {
+	if s != "" && s != "L" && s != "Q" && s != "T" && c.err == nil {
+		c.err = &ParseError{"target status", s, "must be one of LQT"}
+	}
+	return firstRune(s)
+}
+
+func parseRMCTime(parts []string) (time.Time, error) {
+	return time.Parse("150405.99 020106 UTC", parts[1]+" "+parts[9]+" UTC")
+}
+
+// parseTimeOfDay parses an hhmmss.ss time, as found in sentences that
+// don't carry a date.  The resulting time is on January 1st, year 0.
+func parseTimeOfDay(s string) (time.Time, error) {
+	return time.Parse("150405 UTC", s+" UTC")
+}
+
+// sentenceTime extracts the timestamp from sentences carrying one,
+// in UTC.  For sentences with only a time of day, the date is January
+// 1st, year 0.
+func sentenceTime(typ string, parts []string) (time.Time, bool) {
+	var t time.Time
+	var err error
+	switch {
+	case typ == "RMC" && len(parts) >= 10:
+		t, err = parseRMCTime(parts)
+	case typ == "GGA" && len(parts) >= 2:
+		t, err = parseTimeOfDay(parts[1])
+	case typ == "GLL" && len(parts) >= 6:
+		t, err = parseTimeOfDay(parts[5])
+	case typ == "ZDA":
+		t, err = parseZDATime(parts)
+		t = ZDA{Timestamp: t}.UTC()
+	default:
+		return time.Time{}, false
+	}
+	return t, err == nil
+}
+
 /*
    0:   RMC          Recommended Minimum sentence C
    1:   123519       Fix taken at 12:35:19 UTC
@@ -92,8 +262,9 @@ func (c *cumulativeErrorParser) parseDMS(s, ref string) float64 {
    8:   084.4        Track angle in degrees True
    9:   230394       Date - 23rd of March 1994
    10,11:  003.1,W      Magnetic Variation
+   12:  A            FAA mode indicator (NMEA 2.3 and later)
 */
-func rmcParser(parts []string, handler interface{}) error {
+func rmcParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(RMCHandler)
 	if !ok {
 		return nil
@@ -103,12 +274,12 @@ func rmcParser(parts []string, handler interface{}) error {
 		return errShortMsg
 	}
 
-	t, err := time.Parse("150405.99 020106 UTC", parts[1]+" "+parts[9]+" UTC")
+	t, err := parseRMCTime(parts)
 	if err != nil {
 		return err
 	}
 
-	cp := &cumulativeErrorParser{}
+	cp := p.newParser()
 
 	lat := cp.parseDMS(parts[3], parts[4])
 	lon := cp.parseDMS(parts[5], parts[6])
@@ -122,23 +293,60 @@ func rmcParser(parts []string, handler interface{}) error {
 		}
 	}
 
+	var mode Mode
+	if len(parts) > 12 {
+		mode = cp.parseMode(parts[12])
+	}
+
 	if cp.err != nil {
 		return cp.err
 	}
 
-	h.HandleRMC(RMC{
-		Timestamp: t,
-		Status:    rune(parts[2][0]),
-		Latitude:  lat,
-		Longitude: lon,
-		Speed:     speed,
-		Angle:     angle,
-		Magvar:    magvar,
-	})
+	if parts[10] == "" && p.MagneticVariation != nil {
+		magvar = p.MagneticVariation(lat, lon, t)
+	}
+
+	var status rune
+	if parts[2] != "" {
+		status = rune(parts[2][0])
+	}
+
+	rmc := RMC{
+		Timestamp:   t,
+		Status:      status,
+		Latitude:    lat,
+		Longitude:   lon,
+		Speed:       speed,
+		Angle:       angle,
+		Magvar:      magvar,
+		Mode:        mode,
+		DataID:      p.dataID,
+		ExtraFields: extraFields(parts, 13),
+	}
+
+	if q, ok := modeQualities[mode]; ok && p.reject(q, rmc) {
+		return nil
+	}
+
+	h.HandleRMC(rmc)
 
 	return nil
 }
 
+// modeQualities maps FAA mode indicators to their equivalent GGA fix
+// quality.
+var modeQualities = map[Mode]FixQuality{
+	Autonomous:   GPSFix,
+	Differential: DGPSFix,
+	Estimated:    EstimatedFix,
+	FloatRTK:     FloatRealTimeKinematicFix,
+	Manual:       ManualInputModeFix,
+	NotValid:     InvalidFix,
+	RTK:          RealTimeKinematicFix,
+	Precise:      PPSFix,
+	Simulator:    SimulationModeFix,
+}
+
 /*
 VTG - Velocity made good. The gps receiver may use the LC prefix
 instead of GP if it is emulating Loran output.
@@ -151,8 +359,9 @@ where:
         // 3,4:  034.4,M      Magnetic track made good
         // 5,6:  005.5,N      Ground speed, knots
         // 7,8:  010.2,K      Ground speed, Kilometers per hour
+        // 9:    A            FAA mode indicator (NMEA 2.3 and later)
 */
-func vtgParser(parts []string, handler interface{}) error {
+func vtgParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(VTGHandler)
 	if !ok {
 		return nil
@@ -162,18 +371,33 @@ func vtgParser(parts []string, handler interface{}) error {
 		return fmt.Errorf("unexpected VTG packet: %#v", parts)
 	}
 
-	cp := &cumulativeErrorParser{}
+	cp := p.newParser()
 	vtg := VTG{
-		True:     cp.parseFloat(parts[1]),
-		Magnetic: cp.parseFloat(parts[3]),
-		Knots:    cp.parseFloat(parts[5]),
-		KMH:      cp.parseFloat(parts[7]),
+		True:        cp.parseFloat(parts[1]),
+		Magnetic:    cp.parseFloat(parts[3]),
+		Knots:       cp.parseFloat(parts[5]),
+		KMH:         cp.parseFloat(parts[7]),
+		DataID:      p.dataID,
+		ExtraFields: extraFields(parts, 10),
+	}
+	if len(parts) > 9 {
+		vtg.Mode = cp.parseMode(parts[9])
 	}
+	vtg.Valid = (parts[5] != "" || parts[7] != "") && vtg.Mode != NotValid
 
 	if cp.err != nil {
 		return cp.err
 	}
 
+	if parts[5] != "" && parts[7] != "" && !p.speedsAgree(vtg) {
+		if p.Strict {
+			return &ParseError{"speed", parts[5] + "," + parts[7], "knots and km/h disagree"}
+		}
+		if p.OnSpeedMismatch != nil {
+			p.OnSpeedMismatch(vtg)
+		}
+	}
+
 	h.HandleVTG(vtg)
 
 	return nil
@@ -205,7 +429,7 @@ Where:
      *47          the checksum data, always begins with *
 
 */
-func ggaParser(parts []string, handler interface{}) error {
+func ggaParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(GGAHandler)
 	if !ok {
 		return nil
@@ -215,13 +439,13 @@ func ggaParser(parts []string, handler interface{}) error {
 		return fmt.Errorf("unexpected GGA packet: %#v", parts)
 	}
 
-	t, err := time.Parse("150405 UTC", parts[1]+" UTC")
+	t, err := parseTimeOfDay(parts[1])
 	if err != nil {
 		return err
 	}
 
-	cp := &cumulativeErrorParser{}
-	h.HandleGGA(GGA{
+	cp := p.newParser()
+	gga := GGA{
 		Taken:              t,
 		Latitude:           cp.parseDMS(parts[2], parts[3]),
 		Longitude:          cp.parseDMS(parts[4], parts[5]),
@@ -230,9 +454,21 @@ func ggaParser(parts []string, handler interface{}) error {
 		NumSats:            cp.parseInt(parts[7]),
 		Altitude:           cp.parseFloat(parts[9]),
 		GeoidHeight:        cp.parseFloat(parts[11]),
-	})
+		DataID:             p.dataID,
+		ExtraFields:        extraFields(parts, 15),
+	}
 
-	return cp.err
+	if cp.err != nil {
+		return cp.err
+	}
+
+	if p.reject(gga.Quality, gga) {
+		return nil
+	}
+
+	h.HandleGGA(gga)
+
+	return nil
 }
 
 /*
@@ -247,18 +483,19 @@ Where:
      15.    2.5      PDOP (dilution of precision)
      16. 1.3      Horizontal dilution of precision (HDOP)
      17. 2.1      Vertical dilution of precision (VDOP)
+     18. 1        System ID (NMEA 4.10 and later)
 */
-func gsaParser(parts []string, handler interface{}) error {
+func gsaParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(GSAHandler)
 	if !ok {
 		return nil
 	}
 
-	if len(parts) != 18 {
+	if len(parts) != 18 && len(parts) != 19 {
 		return fmt.Errorf("unexpected GSA packet: %#v (len=%v)", parts, len(parts))
 	}
 
-	cp := &cumulativeErrorParser{}
+	cp := p.newParser()
 	sats := []int{}
 	for _, s := range parts[3:15] {
 		if s != "" {
@@ -266,14 +503,19 @@ func gsaParser(parts []string, handler interface{}) error {
 		}
 	}
 
-	h.HandleGSA(GSA{
+	gsa := GSA{
 		Auto:     parts[1] == "A",
 		Fix:      GSAFix(cp.parseInt(parts[2])),
 		SatsUsed: sats,
 		PDOP:     cp.parseFloat(parts[15]),
 		HDOP:     cp.parseFloat(parts[16]),
 		VDOP:     cp.parseFloat(parts[17]),
-	})
+	}
+	if len(parts) > 18 {
+		gsa.SystemID = cp.parseInt(parts[18])
+	}
+
+	h.HandleGSA(gsa)
 
 	return cp.err
 }
@@ -287,8 +529,9 @@ Where:
      3,4: 12311.12,W   Longitude 123 deg. 11.12 min. West
      5:   225444       Fix taken at 22:54:44 UTC
      6:   A            Data Active or V (void)
+     7:   A            FAA mode indicator (NMEA 2.3 and later)
 */
-func gllParser(parts []string, handler interface{}) error {
+func gllParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(GLLHandler)
 	if !ok {
 		return nil
@@ -298,18 +541,29 @@ func gllParser(parts []string, handler interface{}) error {
 		return errShortMsg
 	}
 
-	t, err := time.Parse("150405 UTC", parts[5]+" UTC")
+	t, err := parseTimeOfDay(parts[5])
 	if err != nil {
 		return err
 	}
 
-	cp := &cumulativeErrorParser{}
-	h.HandleGLL(GLL{
-		Taken:     t,
-		Latitude:  cp.parseDMS(parts[1], parts[2]),
-		Longitude: cp.parseDMS(parts[3], parts[4]),
-		Active:    parts[6] == "A",
-	})
+	cp := p.newParser()
+	gll := GLL{
+		Taken:       t,
+		Latitude:    cp.parseDMS(parts[1], parts[2]),
+		Longitude:   cp.parseDMS(parts[3], parts[4]),
+		Active:      parts[6] == "A",
+		DataID:      p.dataID,
+		ExtraFields: extraFields(parts, 8),
+	}
+	if len(parts) > 7 {
+		gll.Mode = cp.parseMode(parts[7])
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleGLL(gll)
 	return nil
 }
 
@@ -325,14 +579,25 @@ where:
         5.     xx        local zone hours -13..13
         6.     yy        local zone minutes 0..59
 */
-func zdaParser(parts []string, handler interface{}) error {
+func zdaParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(ZDAHandler)
 	if !ok {
 		return nil
 	}
 
+	ts, err := parseZDATime(parts)
+	if err != nil {
+		return err
+	}
+
+	h.HandleZDA(ZDA{ts})
+
+	return nil
+}
+
+func parseZDATime(parts []string) (time.Time, error) {
 	if len(parts) != 7 || len(parts[1]) < 6 {
-		return fmt.Errorf("unexpected ZDA packet: %#v (len=%v)", parts, len(parts))
+		return time.Time{}, fmt.Errorf("unexpected ZDA packet: %#v (len=%v)", parts, len(parts))
 	}
 
 	cp := &cumulativeErrorParser{}
@@ -354,9 +619,7 @@ func zdaParser(parts []string, handler interface{}) error {
 		int(float64(time.Second)*cp.parseFloat(parts[1][6:])),
 		tz)
 
-	h.HandleZDA(ZDA{ts})
-
-	return cp.err
+	return ts, cp.err
 }
 
 /*
@@ -373,10 +636,11 @@ Where:
       083          Azimuth, degrees
       46           SNR - higher is better
            for up to 4 satellites per sentence
+      (1)          Signal ID (NMEA 4.10 and later)
       *75          the checksum data, always begins with *
 
 */
-func gsvParser(parts []string, handler interface{}) error {
+func gsvParser(p *Processor, parts []string, handler interface{}) error {
 	h, ok := handler.(GSVHandler)
 	if !ok {
 		return nil
@@ -386,142 +650,1148 @@ func gsvParser(parts []string, handler interface{}) error {
 		return errShortMsg
 	}
 
-	cp := &cumulativeErrorParser{}
+	cp := p.newParser()
 	gsv := GSV{
 		InView:         cp.parseInt(parts[3]),
 		SentenceNum:    cp.parseInt(parts[2]),
 		TotalSentences: cp.parseInt(parts[1]),
 	}
 
-	for i := 4; i+4 <= len(parts); i += 4 {
+	fields := parts[4:]
+	blocks := len(fields) / 4
+	for i := 0; i < blocks*4; i += 4 {
 		gsv.SatInfo = append(gsv.SatInfo, GSVSatInfo{
-			cp.parseInt(parts[i]),
-			cp.parseInt(parts[i+1]),
-			cp.parseInt(parts[i+2]),
-			cp.parseInt(parts[i+3]),
+			cp.parseInt(fields[i]),
+			cp.parseInt(fields[i+1]),
+			cp.parseInt(fields[i+2]),
+			cp.parseInt(fields[i+3]),
 		})
 	}
 
+	// A single field following every satellite this sentence should
+	// carry is an NMEA 4.10 signal ID.  Anything else left over is
+	// what remains of a satellite cut off mid-sentence.
+	expected := gsv.InView - 4*(gsv.SentenceNum-1)
+	if expected > 4 {
+		expected = 4
+	}
+	switch leftover := fields[blocks*4:]; {
+	case len(leftover) == 1 && blocks == expected:
+		if leftover[0] != "" {
+			id, err := strconv.ParseUint(leftover[0], 16, 8)
+			if err != nil && cp.err == nil {
+				cp.err = &ParseError{"signal ID", leftover[0], "not a hex digit"}
+			}
+			gsv.SignalID = int(id)
+		}
+	case len(leftover) > 0:
+		gsv.Truncated = true
+	}
+
+	// Corrupt positions would be plotted in the wrong place.
+	if p.Strict {
+		for _, si := range gsv.SatInfo {
+			switch {
+			case si.Elevation < 0 || si.Elevation > 90:
+				return &ParseError{"elevation", strconv.Itoa(si.Elevation), "out of range"}
+			case si.Azimuth < 0 || si.Azimuth > 359:
+				return &ParseError{"azimuth", strconv.Itoa(si.Azimuth), "out of range"}
+			}
+		}
+	}
+
 	h.HandleGSV(gsv)
 
 	return cp.err
 }
 
-// GSVAccumulator combines several GSV structures into a single value.
-//
-// GSV state is split sacross multiple sentences that are parsed
-// independently.  Applications that want to see the complete state
-// can use GSVAccumulator as a helper to stitch the parts together
-// into a single unified state.
-type GSVAccumulator struct {
-	InView  int
-	Parts   int
-	prev    int
-	SatInfo []GSVSatInfo
-}
+/*
+	$GPRTE,2,1,c,0,PBRCPK,PBRTO,PTELGR,PPLAND,PYAMBU,PPFAIR,PWARRN,PMORTL,PLISMR*73
 
-// Add a GSV to the accumulating GSV state.
-//
-// Add returns true whenever the invocation left accumulation in a
-// complete state.  You may continue to call Add after a complete
-// state -- the state will simply be reset.
-func (g *GSVAccumulator) Add(a GSV) bool {
-	if a.TotalSentences != g.Parts || a.SentenceNum != g.prev+1 {
-		g.InView = a.InView
-		g.Parts = a.TotalSentences
-		g.prev = a.SentenceNum
-		g.SatInfo = a.SatInfo
+Where:
 
-		if a.SentenceNum != 1 {
-			g.prev = 0
-			g.SatInfo = nil
+	1: 2         Number of sentences in sequence
+	2: 1         Sentence number
+	3: c         'c' = Current active route, 'w' = waypoint list starts
+	             with destination waypoint
+	4: 0         Name or number of the active route
+	5-*: PBRCPK  Names of waypoints in route
+*/
+func rteParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(RTEHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 5 || (parts[3] != "c" && parts[3] != "w") {
+		return fmt.Errorf("unexpected RTE packet: %#v", parts)
+	}
+
+	cp := p.newParser()
+	rte := RTE{
+		TotalSentences: cp.parseInt(parts[1]),
+		SentenceNum:    cp.parseInt(parts[2]),
+		Complete:       parts[3] == "c",
+		RouteID:        parts[4],
+	}
+	for _, w := range parts[5:] {
+		if w != "" {
+			rte.Waypoints = append(rte.Waypoints, w)
 		}
-		return a.TotalSentences == 1
 	}
 
-	g.prev = a.SentenceNum
-	g.SatInfo = append(g.SatInfo, a.SatInfo...)
+	if cp.err != nil {
+		return cp.err
+	}
 
-	return g.prev == g.Parts
+	h.HandleRTE(rte)
+
+	return nil
 }
 
-func checkChecksum(line string) bool {
-	cs := 0
-	if len(line) < 4 {
-		return false
-	}
+/*
+	$GPWPL,4917.16,N,12310.64,W,003*65
 
-	if line[0] != '$' {
-		return false
+Where:
+
+	1,2: 4917.16,N   Latitude of waypoint
+	3,4: 12310.64,W  Longitude of waypoint
+	5:   003         Waypoint name
+*/
+func wplParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(WPLHandler)
+	if !ok {
+		return nil
 	}
-	if line[len(line)-3] != '*' {
-		return false
+
+	if len(parts) < 6 {
+		return errShortMsg
 	}
-	exp, err := strconv.ParseInt(line[len(line)-2:], 16, 64)
-	if err != nil {
-		return false
+
+	cp := p.newParser()
+	wpl := WPL{
+		Latitude:  cp.parseDMS(parts[1], parts[2]),
+		Longitude: cp.parseDMS(parts[3], parts[4]),
+		Name:      parts[5],
 	}
 
-	for _, c := range line[1:] {
-		if c == '*' {
-			break
-		}
-		cs = cs ^ int(c)
+	if cp.err != nil {
+		return cp.err
 	}
 
-	return cs == int(exp)
+	h.HandleWPL(wpl)
+
+	return nil
 }
 
-func parseMessage(line string, handler interface{}) error {
-	if !checkChecksum(line) {
-		// skip bad checksums
-		return errBadChecksum
+/*
+	$YXXDR,C,19.52,C,TEMP,P,1.02481,B,PRESS,H,52.7,P,RH*hh
+
+Where:
+
+	1: C         Transducer type (C = temperature, P = pressure, ...)
+	2: 19.52     Measurement
+	3: C         Units of measurement
+	4: TEMP      Transducer name
+	5-*:         More measurements, in groups of four
+*/
+func xdrParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(XDRHandler)
+	if !ok {
+		return nil
 	}
 
-	parts := strings.Split(line[:len(line)-3], ",")
+	if len(parts) < 5 {
+		return errShortMsg
+	}
 
-	var err error
-	if p, ok := parsers[parts[0][3:]]; ok {
-		err = p(parts, handler)
-	} else {
-		return ErrUnhandled
+	cp := p.newParser()
+	xdr := XDR{DataID: p.dataID}
+	for i := 1; i+4 <= len(parts); i += 4 {
+		xdr.Measurements = append(xdr.Measurements, XDRMeasurement{
+			Type:  firstRune(parts[i]),
+			Value: cp.parseFloat(parts[i+1]),
+			Unit:  firstRune(parts[i+2]),
+			Name:  parts[i+3],
+		})
+	}
+
+	if cp.err != nil {
+		return cp.err
 	}
-	return err
+
+	h.HandleXDR(xdr)
+
+	return nil
 }
 
-// ErrorHandler handles error in processing individual messages.  If
-// the error handler returns nil, the processor will keep executing,
-// else Process will return the error the ErrorHandler returned.
-type ErrorHandler func(s string, err error) error
+/*
+	$IIVWR,084,R,10.1,N,5.2,M,18.7,K*hh
+
+Where:
+
+	1,2: 084,R    Wind angle relative to the bow, L = port, R = starboard
+	3,4: 10.1,N   Wind speed, knots
+	5,6: 5.2,M    Wind speed, meters per second
+	7,8: 18.7,K   Wind speed, kilometers per hour
+
+VWT has the same layout, but reports the true wind.
+*/
+func parseWind(p *Processor, parts []string) (Wind, error) {
+	if len(parts) < 9 {
+		return Wind{}, errShortMsg
+	}
+
+	cp := p.newParser()
+	w := Wind{
+		Angle: cp.parseFloat(parts[1]),
+		Side:  firstRune(parts[2]),
+		Knots: cp.parseUnit(parts[3], parts[4], "N"),
+		MPS:   cp.parseUnit(parts[5], parts[6], "M"),
+		KMH:   cp.parseUnit(parts[7], parts[8], "K"),
+	}
+	return w, cp.err
+}
+
+func vwrParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(VWRHandler)
+	if !ok {
+		return nil
+	}
+
+	w, err := parseWind(p, parts)
+	if err != nil {
+		return err
+	}
+
+	h.HandleVWR(VWR{w, p.dataID})
 
-func defaultErrorHandler(s string, err error) error {
 	return nil
 }
 
-// Process all of the NMEA messages from the given reader.
-//
-// The handler satisfies any *Handler interfaces the application
-// requires.  Any unhandled message will be ignored.
-//
-// An optional error handler can decide how to handle any errors that
-// arise in parsing.  The default will ignore parser errors.
-//
-// Process returns nil on EOF.
-func Process(r io.Reader, handler interface{}, errh ErrorHandler) error {
-	if errh == nil {
-		errh = defaultErrorHandler
+func vwtParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(VWTHandler)
+	if !ok {
+		return nil
 	}
-	s := bufio.NewScanner(r)
-	for s.Scan() {
-		if s.Text() == "" {
-			continue
-		}
-		err := parseMessage(s.Text(), handler)
-		if err != nil {
-			if e := errh(s.Text(), err); e != nil {
-				return e
-			}
-		}
+
+	w, err := parseWind(p, parts)
+	if err != nil {
+		return err
 	}
-	return s.Err()
+
+	h.HandleVWT(VWT{w, p.dataID})
+
+	return nil
+}
+
+/*
+	$IIVPW,1.2,N,2.2,K*hh
+
+Where:
+
+	1,2: 1.2,N Speed parallel to the wind, knots
+	3,4: 2.2,K Speed parallel to the wind, kilometers per hour
+*/
+func vpwParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(VPWHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 5 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	vpw := VPW{
+		Knots:  cp.parseUnit(parts[1], parts[2], "N"),
+		KMH:    cp.parseUnit(parts[3], parts[4], "K"),
+		DataID: p.dataID,
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleVPW(vpw)
+
+	return nil
+}
+
+/*
+	$SDDBT,7.8,f,2.4,M,1.3,F*hh
+
+Where:
+
+	1,2: 7.8,f   Depth, feet
+	3,4: 2.4,M   Depth, meters
+	5,6: 1.3,F   Depth, fathoms
+
+DBK (below keel) and DBS (below surface) have the same layout as DBT
+(below transducer).
+*/
+func parseDepth(p *Processor, parts []string) (Depth, error) {
+	if len(parts) < 7 {
+		return Depth{}, errShortMsg
+	}
+
+	cp := p.newParser()
+	d := Depth{
+		Feet:    cp.parseUnit(parts[1], parts[2], "f"),
+		Meters:  cp.parseUnit(parts[3], parts[4], "M"),
+		Fathoms: cp.parseUnit(parts[5], parts[6], "F"),
+	}
+	return d, cp.err
+}
+
+func dbtParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(DBTHandler)
+	if !ok {
+		return nil
+	}
+
+	d, err := parseDepth(p, parts)
+	if err != nil {
+		return err
+	}
+
+	h.HandleDBT(DBT{d, p.dataID})
+
+	return nil
+}
+
+func dbkParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(DBKHandler)
+	if !ok {
+		return nil
+	}
+
+	d, err := parseDepth(p, parts)
+	if err != nil {
+		return err
+	}
+
+	h.HandleDBK(DBK{d, p.dataID})
+
+	return nil
+}
+
+func dbsParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(DBSHandler)
+	if !ok {
+		return nil
+	}
+
+	d, err := parseDepth(p, parts)
+	if err != nil {
+		return err
+	}
+
+	h.HandleDBS(DBS{d, p.dataID})
+
+	return nil
+}
+
+/*
+	$GPTHS,338.01,A*hh
+
+Where:
+
+	1: 338.01    Heading, degrees true
+	2: A         Status: A = autonomous, E = estimated (dead
+	             reckoning), M = manual input, S = simulator,
+	             V = not valid
+*/
+func thsParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(THSHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 3 {
+		return errShortMsg
+	}
+
+	status := firstRune(parts[2])
+	switch status {
+	case 'A', 'E', 'M', 'S', 'V':
+	default:
+		return &ParseError{"status", parts[2], "must be one of AEMSV"}
+	}
+
+	cp := p.newParser()
+	ths := THS{
+		Heading: cp.parseFloat(parts[1]),
+		Status:  status,
+		DataID:  p.dataID,
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleTHS(ths)
+
+	return nil
+}
+
+// parseCrossTrack parses a cross-track error magnitude, direction to
+// steer and unit from three consecutive fields.
+func (c *cumulativeErrorParser) parseCrossTrack(parts []string) CrossTrack {
+	x := CrossTrack{
+		Magnitude:      c.parseFloat(parts[0]),
+		SteerDirection: firstRune(parts[1]),
+		Unit:           firstRune(parts[2]),
+	}
+	switch {
+	case c.err != nil:
+	case parts[1] != "" && parts[1] != "L" && parts[1] != "R":
+		c.err = &ParseError{"steer direction", parts[1], "must be L or R"}
+	case parts[2] != "" && parts[2] != "N" && parts[2] != "K":
+		c.err = &ParseError{"unit", parts[2], "must be N or K"}
+	}
+	return x
+}
+
+/*
+	$GPXTE,A,A,0.67,L,N,A*hh
+
+Where:
+
+	1: A         A = data valid, V = general warning (Loran-C blink
+	             or SNR)
+	2: A         A = data valid, V = Loran-C cycle lock warning
+	3: 0.67      Cross-track error magnitude
+	4: L         Direction to steer, L or R
+	5: N         Units, N = nautical miles, K = kilometers
+	6: A         FAA mode indicator (NMEA 2.3 and later)
+*/
+func xteParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(XTEHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 6 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	xte := XTE{
+		Valid:      parts[1] == "A",
+		CycleLock:  parts[2] == "A",
+		CrossTrack: cp.parseCrossTrack(parts[3:6]),
+		DataID:     p.dataID,
+	}
+	if len(parts) > 6 {
+		xte.Mode = cp.parseMode(parts[6])
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleXTE(xte)
+
+	return nil
+}
+
+/*
+	$GPXTC,0.67,L,N*hh
+
+Where:
+
+	1: 0.67      Cross-track error magnitude
+	2: L         Direction to steer, L or R
+	3: N         Units, N = nautical miles, K = kilometers
+*/
+func xtcParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(XTCHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 4 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	xtc := XTC{cp.parseCrossTrack(parts[1:4]), p.dataID}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleXTC(xtc)
+
+	return nil
+}
+
+/*
+	$GPWCV,0.0,N,DEST*hh
+
+Where:
+
+	1,2: 0.0,N   Velocity towards the waypoint, knots
+	3:   DEST    Waypoint ID
+	4:   A       FAA mode indicator (NMEA 2.3 and later)
+*/
+func wcvParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(WCVHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 4 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	wcv := WCV{
+		Velocity:   cp.parseUnit(parts[1], parts[2], "N"),
+		Unit:       firstRune(parts[2]),
+		WaypointID: parts[3],
+		DataID:     p.dataID,
+	}
+	if len(parts) > 4 {
+		wcv.Mode = cp.parseMode(parts[4])
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleWCV(wcv)
+
+	return nil
+}
+
+/*
+	$GPSTN,01*hh
+
+Where:
+
+	1: 01        Talker ID number, identifying the sensor sending the
+	             following sentences
+*/
+func stnParser(p *Processor, parts []string, handler interface{}) error {
+	if len(parts) < 2 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	stn := STN{cp.parseInt(parts[1])}
+	if cp.err != nil {
+		return cp.err
+	}
+	p.dataID = stn.DataID
+	p.stnNext = true
+
+	if h, ok := handler.(STNHandler); ok {
+		h.HandleSTN(stn)
+	}
+
+	return nil
+}
+
+/*
+	$GPMSS,55,27,320.0,100,*hh
+
+Where:
+
+	1: 55        Signal strength, dB re 1 uV/m
+	2: 27        Signal to noise ratio, dB
+	3: 320.0     Beacon frequency, kHz
+	4: 100       Beacon bit rate, bits per second
+	5: (empty)   Channel number (NMEA 2.3 and later)
+*/
+func mssParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(MSSHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 5 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	mss := MSS{
+		SignalStrength: cp.parseFloat(parts[1]),
+		SNR:            cp.parseFloat(parts[2]),
+		Frequency:      cp.parseFloat(parts[3]),
+		BitRate:        cp.parseInt(parts[4]),
+		DataID:         p.dataID,
+	}
+	if len(parts) > 5 {
+		mss.Channel = cp.parseInt(parts[5])
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleMSS(mss)
+
+	return nil
+}
+
+/*
+	$GPMSK,318.0,A,100,M,2*hh
+
+Where:
+
+	1: 318.0     Beacon frequency, kHz
+	2: A         Frequency mode, A = automatic, M = manual
+	3: 100       Beacon bit rate, bits per second
+	4: M         Bit rate mode, A = automatic, M = manual
+	5: 2         Interval for sending MSS status, seconds
+*/
+func mskParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(MSKHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 6 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	msk := MSK{
+		Frequency:      cp.parseFloat(parts[1]),
+		FrequencyAuto:  parts[2] == "A",
+		BitRate:        cp.parseInt(parts[3]),
+		BitRateAuto:    parts[4] == "A",
+		StatusInterval: cp.parseInt(parts[5]),
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleMSK(msk)
+
+	return nil
+}
+
+/*
+	$IIVDR,010.0,T,011.0,M,02.5,N*hh
+
+Where:
+
+	1,2: 010.0,T Direction (set), degrees true
+	3,4: 011.0,M Direction (set), degrees magnetic
+	5,6: 02.5,N  Current speed (drift), knots
+*/
+func vdrParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(VDRHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 7 || parts[2] != "T" || parts[4] != "M" || parts[6] != "N" {
+		return fmt.Errorf("unexpected VDR packet: %#v", parts)
+	}
+
+	cp := p.newParser()
+	vdr := VDR{
+		DirectionTrue:     cp.parseFloat(parts[1]),
+		DirectionMagnetic: cp.parseFloat(parts[3]),
+		SpeedKnots:        cp.parseFloat(parts[5]),
+		DataID:            p.dataID,
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleVDR(vdr)
+
+	return nil
+}
+
+/*
+	$ERRSA,1.2,A,-0.5,A*hh
+
+Where:
+
+	1: 1.2   Starboard (or single) rudder angle, degrees, negative to port
+	2: A     Status, A = valid, V = invalid
+	3: -0.5  Port rudder angle, degrees
+	4: A     Status, A = valid, V = invalid
+*/
+func rsaParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(RSAHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 5 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	rsa := RSA{
+		Starboard:      cp.parseFloat(parts[1]),
+		StarboardValid: parts[2] == "A",
+		Port:           cp.parseFloat(parts[3]),
+		PortValid:      parts[4] == "A",
+		DataID:         p.dataID,
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleRSA(rsa)
+
+	return nil
+}
+
+// parseAutopilot parses the ten fields shared by APA and APB.
+func (c *cumulativeErrorParser) parseAutopilot(parts []string) Autopilot {
+	a := Autopilot{
+		Valid:         parts[0] == "A",
+		CycleLock:     parts[1] == "A",
+		CrossTrack:    c.parseCrossTrack(parts[2:5]),
+		ArrivalCircle: parts[5] == "A",
+		Perpendicular: parts[6] == "A",
+		Bearing:       c.parseFloat(parts[7]),
+		BearingRef:    firstRune(parts[8]),
+		DestinationID: parts[9],
+	}
+	if c.err == nil && parts[8] != "" && parts[8] != "T" && parts[8] != "M" {
+		c.err = &ParseError{"bearing reference", parts[8], "must be T or M"}
+	}
+	return a
+}
+
+/*
+	$GPAPA,A,A,0.10,R,N,V,V,011,M,DEST*hh
+
+Where:
+
+	1:  A     A = data valid, V = general warning (Loran-C blink or SNR)
+	2:  A     A = data valid, V = Loran-C cycle lock warning
+	3:  0.10  Cross-track error magnitude
+	4:  R     Direction to steer, L or R
+	5:  N     Cross-track units, N = nautical miles, K = kilometers
+	6:  V     A = arrival circle entered
+	7:  V     A = perpendicular passed at waypoint
+	8:  011   Bearing origin to destination
+	9:  M     M = magnetic, T = true
+	10: DEST  Destination waypoint ID
+*/
+func apaParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(APAHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 11 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	apa := APA{cp.parseAutopilot(parts[1:11]), p.dataID}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleAPA(apa)
+
+	return nil
+}
+
+/*
+	$RATTM,11,25.3,13.7,T,7.0,20.0,T,10.1,20.2,N,TGT,T,,100021.00,A*hh
+
+Where:
+
+	1:    11        Target number
+	2:    25.3      Target distance from own ship
+	3,4:  13.7,T    Bearing from own ship, T = true, R = relative
+	5:    7.0       Target speed
+	6,7:  20.0,T    Target course, T = true, R = relative
+	8:    10.1      Distance of closest point of approach
+	9:    20.2      Time to CPA, minutes, negative once passed
+	10:   N         Distance and speed units, K, N or S
+	11:   TGT       Target name
+	12:   T         Status, L = lost, Q = acquiring, T = tracking
+	13:   (empty)   R = reference target
+	14:   100021.00 UTC time (NMEA 3.0 and later)
+	15:   A         Acquisition, A = auto, M = manual, R = reported
+*/
+func ttmParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(TTMHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 14 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	ttm := TTM{
+		Number:     cp.parseInt(parts[1]),
+		Distance:   cp.parseFloat(parts[2]),
+		Bearing:    cp.parseFloat(parts[3]),
+		BearingRef: firstRune(parts[4]),
+		Speed:      cp.parseFloat(parts[5]),
+		Course:     cp.parseFloat(parts[6]),
+		CourseRef:  firstRune(parts[7]),
+		CPA:        cp.parseFloat(parts[8]),
+		TCPA:       cp.parseFloat(parts[9]),
+		Unit:       firstRune(parts[10]),
+		Name:       parts[11],
+		Status:     cp.parseTargetStatus(parts[12]),
+		Reference:  parts[13] == "R",
+		DataID:     p.dataID,
+	}
+	if len(parts) > 14 {
+		ttm.Taken = cp.parseTimeOfDay(parts[14])
+	}
+	if len(parts) > 15 {
+		ttm.Acquisition = firstRune(parts[15])
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleTTM(ttm)
+
+	return nil
+}
+
+/*
+	$RATLL,01,4807.038,N,01131.000,E,TGT,123519.00,T,*hh
+
+Where:
+
+	1:   01          Target number
+	2,3: 4807.038,N  Target latitude
+	4,5: 01131.000,E Target longitude
+	6:   TGT         Target name
+	7:   123519.00   UTC time
+	8:   T           Status, L = lost, Q = acquiring, T = tracking
+	9:   (empty)     R = reference target
+*/
+func tllParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(TLLHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 9 {
+		return errShortMsg
+	}
+
+	cp := p.newParser()
+	tll := TLL{
+		Number:    cp.parseInt(parts[1]),
+		Latitude:  cp.parseDMS(parts[2], parts[3]),
+		Longitude: cp.parseDMS(parts[4], parts[5]),
+		Name:      parts[6],
+		Taken:     cp.parseTimeOfDay(parts[7]),
+		Status:    cp.parseTargetStatus(parts[8]),
+		DataID:    p.dataID,
+	}
+	if len(parts) > 9 {
+		tll.Reference = parts[9] == "R"
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleTLL(tll)
+
+	return nil
+}
+
+/*
+	$GPGST,024603.00,3.2,6.6,4.7,47.3,5.8,5.6,22.0*58
+
+Where:
+
+	1: 024603.00 UTC time of the associated GGA fix
+	2: 3.2       RMS of the standard deviation of the range inputs
+	3: 6.6       Standard deviation of the semi-major axis of the error
+	             ellipse (meters)
+	4: 4.7       Standard deviation of the semi-minor axis (meters)
+	5: 47.3      Orientation of the semi-major axis (degrees from true
+	             north)
+	6: 5.8       Standard deviation of latitude error (meters)
+	7: 5.6       Standard deviation of longitude error (meters)
+	8: 22.0      Standard deviation of altitude error (meters)
+*/
+func gstParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(GSTHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 9 {
+		return errShortMsg
+	}
+
+	t, err := parseTimeOfDay(parts[1])
+	if err != nil {
+		return err
+	}
+
+	cp := p.newParser()
+	gst := GST{
+		Taken:       p.withDate(t),
+		RMS:         cp.parseFloat(parts[2]),
+		SemiMajor:   cp.parseFloat(parts[3]),
+		SemiMinor:   cp.parseFloat(parts[4]),
+		Orientation: cp.parseFloat(parts[5]),
+		LatError:    cp.parseFloat(parts[6]),
+		LonError:    cp.parseFloat(parts[7]),
+		AltError:    cp.parseFloat(parts[8]),
+		DataID:      p.dataID,
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandleGST(gst)
+
+	return nil
+}
+
+// extraFields returns a copy of any fields following the first n, for
+// the ExtraFields of a message, or nil if there are none.  Only the
+// RMC, GGA, GLL and VTG parsers keep them; see the package doc.
+func extraFields(parts []string, n int) []string {
+	if len(parts) <= n {
+		return nil
+	}
+	return append([]string(nil), parts[n:]...)
+}
+
+// firstRune returns the first character of a single character field,
+// or 0 if the field is empty.
+func firstRune(s string) rune {
+	if s == "" {
+		return 0
+	}
+	return rune(s[0])
+}
+
+// GSVAccumulator combines several GSV structures into a single value.
+//
+// GSV state is split sacross multiple sentences that are parsed
+// independently.  Applications that want to see the complete state
+// can use GSVAccumulator as a helper to stitch the parts together
+// into a single unified state.
+//
+// A set whose later sentences are lost never completes.  Set Partial
+// to be told about such sets rather than silently dropping them.
+//
+// There's no limit on how long a set may stay pending: every GSV
+// either continues the pending set or abandons it, so a set can only
+// be left pending when GSV sentences stop altogether, which counting
+// GSV sentences can't detect.  Call Flush once per fix cycle to
+// bound it instead.
+type GSVAccumulator struct {
+	InView  int
+	Parts   int
+	prev    int
+	SatInfo []GSVSatInfo
+
+	// Partial, if not nil, is called with whatever satellites an
+	// incomplete set had accumulated when it's abandoned, either
+	// because a new set began or Flush was called.
+	Partial func(inView int, sats []GSVSatInfo)
+}
+
+func (g *GSVAccumulator) pending() bool {
+	return g.prev > 0 && g.prev < g.Parts
+}
+
+// Add a GSV to the accumulating GSV state.
+//
+// Add returns true whenever the invocation left accumulation in a
+// complete state.  You may continue to call Add after a complete
+// state -- the state will simply be reset.
+func (g *GSVAccumulator) Add(a GSV) bool {
+	if a.TotalSentences != g.Parts || a.SentenceNum != g.prev+1 {
+		if g.Partial != nil && g.pending() {
+			g.Partial(g.InView, g.SatInfo)
+		}
+		g.InView = a.InView
+		g.Parts = a.TotalSentences
+		g.prev = a.SentenceNum
+		g.SatInfo = a.SatInfo
+
+		if a.SentenceNum != 1 {
+			g.prev = 0
+			g.SatInfo = nil
+		}
+		return a.TotalSentences == 1
+	}
+
+	g.prev = a.SentenceNum
+	g.SatInfo = append(g.SatInfo, a.SatInfo...)
+
+	return g.prev == g.Parts
+}
+
+// Flush abandons any incomplete set, reporting it to Partial.  Call
+// it at a boundary where no more of the set can be expected, e.g.
+// from an EpochHandler.
+func (g *GSVAccumulator) Flush() {
+	if g.pending() {
+		if g.Partial != nil {
+			g.Partial(g.InView, g.SatInfo)
+		}
+		g.prev = 0
+		g.SatInfo = nil
+	}
+}
+
+// Checksum returns the NMEA checksum of a sentence: the XOR of every
+// byte between the leading $ (if present) and the * (if present).
+func Checksum(s string) byte {
+	if len(s) > 0 && (s[0] == '$' || s[0] == '!') {
+		s = s[1:]
+	}
+	var cs byte
+	for i := 0; i < len(s) && s[i] != '*'; i++ {
+		cs ^= s[i]
+	}
+	return cs
+}
+
+func checkChecksum(line string) bool {
+	if len(line) < 4 {
+		return false
+	}
+
+	if line[0] != '$' && line[0] != '!' {
+		return false
+	}
+	if line[len(line)-3] != '*' {
+		return false
+	}
+	hi, ok1 := unhex(line[len(line)-2])
+	lo, ok2 := unhex(line[len(line)-1])
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	return Checksum(line) == hi<<4|lo
+}
+
+// unhex returns the value of a hex digit of either case.
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// Valid reports whether a line is a sentence with a valid checksum.
+// The checksum may be written with either upper or lower case hex
+// digits.
+func Valid(line string) bool {
+	return checkChecksum(line)
+}
+
+// AppendChecksum returns the sentence s with its checksum appended as
+// an asterisk followed by two upper case hex digits, as the standard
+// requires.  s must not already have a checksum.
+func AppendChecksum(s string) string {
+	return fmt.Sprintf("%s*%02X", s, Checksum(s))
+}
+
+// ErrorHandler handles error in processing individual messages.  If
+// the error handler returns nil, the processor will keep executing,
+// else Process will return the error the ErrorHandler returned.
+type ErrorHandler func(s string, err error) error
+
+func defaultErrorHandler(s string, err error) error {
+	return nil
+}
+
+// Process all of the NMEA messages from the given reader.
+//
+// The handler satisfies any *Handler interfaces the application
+// requires.  Any unhandled message will be ignored.
+//
+// An optional error handler can decide how to handle any errors that
+// arise in parsing.  The default will ignore parser errors.
+//
+// Sentences run together on a single line (e.g. by a capture that
+// lost its line terminators) are split apart at each $ or ! and
+// parsed, and checksummed, independently.
+//
+// When the input ends, a handler implementing Flusher is flushed.
+// It isn't flushed if the error handler aborts processing.
+//
+// Process returns nil on a clean EOF.  A final line without a line
+// terminator is parsed like any other, but if it fails checksum
+// validation the input was probably cut off mid-sentence, and
+// Process returns ErrTruncated.
+func Process(r io.Reader, handler interface{}, errh ErrorHandler) error {
+	return (&Processor{}).Process(r, handler, errh)
+}
+
+// ParseLine parses a single sentence (without its line terminator),
+// returning the message it describes, e.g. an RMC.
+//
+// ParseLine never panics, however malformed the input.  Sentences of
+// unknown types return ErrUnhandled.  A sentence the Processor
+// deliberately withholds (e.g. a rejected fix) returns nil and no
+// error.
+func ParseLine(line string) (interface{}, error) {
+	return (&Processor{}).ParseLine(line)
+}
+
+// ProcessChan processes NMEA messages from a channel of lines, each
+// holding a single sentence without its line terminator.
+//
+// The handler and error handler behave as they do for Process.
+// ProcessChan returns nil once the channel is closed.  If an error
+// aborts processing, the remaining lines are not drained.
+func ProcessChan(lines <-chan string, handler interface{}, errh ErrorHandler) error {
+	return (&Processor{}).ProcessChan(lines, handler, errh)
+}
+
+// ProcessMulti processes NMEA messages from several readers at once,
+// e.g. to merge the streams of multiple receivers.
+//
+// Each reader is read concurrently, but lines are dispatched one at a
+// time, so calls to the handler and error handler remain serialized
+// and need no locking.  Lines from different readers are interleaved
+// in the order they arrive; lines are never split.
+//
+// ProcessMulti returns once all readers reach EOF, returning the
+// first read error encountered, if any.  If the error handler aborts
+// processing, ProcessMulti returns immediately; readers blocked in a
+// Read call are abandoned until that call returns.
+func ProcessMulti(readers []io.Reader, handler interface{}, errh ErrorHandler) error {
+	return (&Processor{}).ProcessMulti(readers, handler, errh)
+}
+
+// ProcessLog processes a captured log of NMEA messages, as Process
+// does, but first unwraps each line from common capture formats:
+// anything preceding the sentence, such as a timestamp
+// ("1520000000.123: $GPRMC,..."), is stripped, and lines of hex
+// encoded bytes ("24 47 50 52 4D 43 ...") are decoded.
+func ProcessLog(r io.Reader, handler interface{}, errh ErrorHandler) error {
+	return (&Processor{}).ProcessLog(r, handler, errh)
 }
@@ -24,15 +24,38 @@ func TestChecksum(t *testing.T) {
 		"$*xx": false,
 		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74": true,
 		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72": false,
+		"$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7D":           true,
+		"$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7d":           true,
+		"$GPDBT,0.0,f*0D": true,
+		"$GPDBT,0.0,f*0d": true,
+		"$GPDBT,0.0,f*D":  false,
+		"$GPDBT,0.0,f*+D": false,
 	}
 
 	for in, exp := range tests {
-		if checkChecksum(in) != exp {
+		if Valid(in) != exp {
 			t.Errorf("Failed on %v/%v", in, exp)
 		}
 	}
 }
 
+func TestAppendChecksum(t *testing.T) {
+	tests := map[string]string{
+		"$GPDBT,0.0,f": "$GPDBT,0.0,f*0D",
+		"$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25": "$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7D",
+		"$": "$*00",
+	}
+	for in, exp := range tests {
+		got := AppendChecksum(in)
+		if got != exp {
+			t.Errorf("AppendChecksum(%q) = %q, want %q", in, got, exp)
+		}
+		if !Valid(got) {
+			t.Errorf("Expected %q to be valid", got)
+		}
+	}
+}
+
 func TestQualityString(t *testing.T) {
 	tests := map[string]string{
 		InvalidFix.String(): "invalid fix",
@@ -49,6 +72,8 @@ func TestGPSFixQualityString(t *testing.T) {
 	tests := map[FixQuality]string{
 		InvalidFix:      "invalid fix",
 		PPSFix:          "pps",
+		SBASFix:         "sbas",
+		FixQuality(10):  "[Invalid Fix Value: 10]",
 		FixQuality(-1):  "[Invalid Fix Value: -1]",
 		FixQuality(100): "[Invalid Fix Value: 100]",
 	}
@@ -81,7 +106,7 @@ func TestSampleParsing(t *testing.T) {
 		if s == "" {
 			continue
 		}
-		if err := parseMessage(s, nil); err != nil {
+		if err := (&Processor{}).parseMessage(s, nil); err != nil {
 			t.Errorf("Error parsing %q:  %v", s, err)
 		}
 	}
@@ -94,6 +119,35 @@ func TestSampleProcessing(t *testing.T) {
 	}
 }
 
+func TestProcessChan(t *testing.T) {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, s := range strings.Split(ubloxSample, "\n") {
+			ch <- s
+		}
+	}()
+
+	h := &testUnion{}
+	err := ProcessChan(ch, h, func(s string, err error) error {
+		return fmt.Errorf("parsing %q: %v", s, err)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error, got %v", err)
+	}
+	if h.zda.Timestamp.IsZero() || h.gsv.SentenceNum != 4 {
+		t.Errorf("Expected the whole sample to be handled, got %#v", h)
+	}
+
+	ch = make(chan string, 2)
+	ch <- "$GPGSV,4,1,1"
+	ch <- ubloxSample[:strings.Index(ubloxSample, "\n")]
+	close(ch)
+	if err := ProcessChan(ch, h, func(s string, e error) error { return e }); err == nil {
+		t.Errorf("Expected error parsing junk, got nil")
+	}
+}
+
 func ExampleProcess() {
 	f, err := os.Open("/dev/gps")
 	if err != nil {
@@ -183,6 +237,68 @@ func TestCumulativeErrorParser(t *testing.T) {
 	}
 }
 
+func TestDMSMinutes(t *testing.T) {
+	dtests := []struct {
+		ina, inb string
+		exp      float64
+		experr   bool
+	}{
+		{"3723.0283700", "N", 37.383806166666666, false},
+		{"12159.3985300", "W", -121.9899755, false},
+		{"3759.99999", "N", 37.99999983333333, false},
+		{"3760.00000", "N", 0, true},
+		{"3799.5", "S", 0, true},
+		{"12160.0", "E", 0, true},
+		{"37-1.5", "N", 0, true},
+	}
+
+	for _, test := range dtests {
+		cp := &cumulativeErrorParser{}
+		got := cp.parseDMS(test.ina, test.inb)
+		if !near(got, test.exp) {
+			t.Errorf("On %q %q, expected %v, got %v", test.ina, test.inb, test.exp, got)
+		}
+		if (cp.err != nil) != test.experr {
+			t.Errorf("On %q %q, expected error=%v  was %v", test.ina, test.inb, test.experr, cp.err)
+		}
+		if _, ok := cp.err.(*ParseError); cp.err != nil && !ok {
+			t.Errorf("On %q %q, expected a ParseError, got %T", test.ina, test.inb, cp.err)
+		}
+	}
+}
+
+func TestDMSHighPrecision(t *testing.T) {
+	// A centimeter is about 1e-7 degrees of latitude, so compare
+	// much more closely than near does.
+	const tolerance = 1e-10
+
+	tests := []struct {
+		ina, inb string
+		exp      float64
+	}{
+		{"3723.0283712", "N", 37.38380618666666666667},
+		{"12159.3985312", "W", -121.98997552},
+		{"3723.028371234", "N", 37.38380618723333333333},
+	}
+	for _, test := range tests {
+		cp := &cumulativeErrorParser{}
+		got := cp.parseDMS(test.ina, test.inb)
+		if cp.err != nil || math.Abs(got-test.exp) > tolerance {
+			t.Errorf("On %q %q, expected %v, got %v (%v)", test.ina, test.inb, test.exp, got, cp.err)
+		}
+	}
+
+	h := &ggaHandler{}
+	line := "$GNGGA,162254.00,3723.0283712,N,12159.3985312,W,4,12,0.52,25.605,M,-25.6,M,1.0,0000*61"
+	if err := (&Processor{}).parseMessage(line, h); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	exp := Position{37.38380618666666666667, -121.98997552}
+	if d := h.gga.Position().DistanceTo(exp); d > 0.001 {
+		t.Errorf("Expected %v within a millimeter, was %vm away", exp, d)
+	}
+}
+
 // Validate type combinations as combined handlers.
 type testUnion struct {
 	vtgHandler
@@ -192,6 +308,24 @@ type testUnion struct {
 	zdaHandler
 	gsvHandler
 	rmcHandler
+	rteHandler
+	wplHandler
+	xdrHandler
+	windHandler
+	depthHandler
+	thsHandler
+	aisHandler
+	crossTrackHandler
+	wcvHandler
+	stnHandler
+	beaconHandler
+	vdrHandler
+	rsaHandler
+	apaHandler
+	targetHandler
+	gstHandler
+	pgrmeHandler
+	pubx00Handler
 }
 
 var _ = interface {
@@ -202,12 +336,37 @@ var _ = interface {
 	RMCHandler
 	VTGHandler
 	ZDAHandler
+	RTEHandler
+	WPLHandler
+	XDRHandler
+	VWRHandler
+	VWTHandler
+	VPWHandler
+	DBTHandler
+	DBKHandler
+	DBSHandler
+	THSHandler
+	AISPositionHandler
+	XTEHandler
+	XTCHandler
+	WCVHandler
+	STNHandler
+	MSSHandler
+	MSKHandler
+	VDRHandler
+	RSAHandler
+	APAHandler
+	TTMHandler
+	TLLHandler
+	GSTHandler
+	PGRMEHandler
+	PUBX00Handler
 }(&testUnion{})
 
 func TestParserUnderflow(t *testing.T) {
 	ah := &testUnion{}
 	for prefix, handler := range parsers {
-		if err := handler([]string{prefix}, ah); err == nil {
+		if err := handler(&Processor{}, []string{prefix}, ah); err == nil {
 			t.Errorf("Unexpected error handling %v: %v", prefix, err)
 		}
 	}
@@ -221,9 +380,17 @@ func (r *rmcHandler) HandleRMC(rmc RMC) {
 	r.rmc = rmc
 }
 
+type rmcRecorder struct {
+	f func(RMC)
+}
+
+func (r *rmcRecorder) HandleRMC(rmc RMC) {
+	r.f(rmc)
+}
+
 func TestRMCMagVar(t *testing.T) {
 	h := &rmcHandler{}
-	err := rmcParser([]string{"$GPRMC", "123519", "A", "4807.038", "N", "01131.000", "E",
+	err := rmcParser(&Processor{}, []string{"$GPRMC", "123519", "A", "4807.038", "N", "01131.000", "E",
 		"022.4", "084.4", "230394", "003.1", "W"}, h)
 	if err != nil {
 		t.Errorf("Failed to parse rmc data: %v", err)
@@ -233,9 +400,41 @@ func TestRMCMagVar(t *testing.T) {
 	}
 }
 
+func TestRMCMagVarCallback(t *testing.T) {
+	var calls int
+	p := &Processor{
+		MagneticVariation: func(lat, lon float64, ts time.Time) float64 {
+			calls++
+			if !near(lat, 48.1173) || !near(lon, 11.516666) || ts.Year() != 1994 {
+				t.Errorf("Unexpected lookup at %v,%v at %v", lat, lon, ts)
+			}
+			return 2.5
+		},
+	}
+
+	h := &rmcHandler{}
+	err := rmcParser(p, []string{"$GPRMC", "123519", "A", "4807.038", "N", "01131.000", "E",
+		"022.4", "084.4", "230394", "", ""}, h)
+	if err != nil {
+		t.Fatalf("Failed to parse rmc data: %v", err)
+	}
+	if !near(h.rmc.Magvar, 2.5) {
+		t.Errorf("Expected magvar from callback, got %v", h.rmc.Magvar)
+	}
+
+	err = rmcParser(p, []string{"$GPRMC", "123519", "A", "4807.038", "N", "01131.000", "E",
+		"022.4", "084.4", "230394", "003.1", "W"}, h)
+	if err != nil {
+		t.Fatalf("Failed to parse rmc data: %v", err)
+	}
+	if !near(h.rmc.Magvar, -3.1) || calls != 1 {
+		t.Errorf("Expected transmitted magvar to be kept, got %v after %v calls", h.rmc.Magvar, calls)
+	}
+}
+
 func TestRMCError(t *testing.T) {
 	h := &rmcHandler{}
-	err := rmcParser([]string{"$GPRMC", "123519", "A", "4807.038", "N", "X1131.000", "E",
+	err := rmcParser(&Processor{}, []string{"$GPRMC", "123519", "A", "4807.038", "N", "X1131.000", "E",
 		"022.4", "084.4", "230394", "003.1", "W"}, h)
 	if err == nil {
 		t.Errorf("Expected to fail to parse rmc data, got: %#v", h.rmc)
@@ -311,7 +510,7 @@ func similar(t *testing.T, a, b interface{}) bool {
 func TestRMCHandling(t *testing.T) {
 	h := &rmcHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := RMC{
 		Timestamp: time.Unix(1152634974, 0).UTC(),
@@ -321,16 +520,41 @@ func TestRMCHandling(t *testing.T) {
 		Speed:     0.82,
 		Angle:     188.36,
 		Magvar:    0,
+		Mode:      Autonomous,
 	}
 	if !similar(t, h.rmc, exp) {
 		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.rmc, exp)
 	}
 }
 
+func TestExtraFields(t *testing.T) {
+	h := &testUnion{}
+	p := &Processor{}
+	for _, s := range strings.Split(ubloxSample, "\n") {
+		p.parseMessage(s, h)
+	}
+	if h.rmc.ExtraFields != nil || h.gga.ExtraFields != nil || h.gll.ExtraFields != nil || h.vtg.ExtraFields != nil {
+		t.Errorf("Expected no extra fields in the sample, got %#v", h)
+	}
+
+	if err := p.parseMessage("$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A,V*0E", h); err != nil {
+		t.Fatalf("Error parsing RMC: %v", err)
+	}
+	if err := p.parseMessage("$GPGGA,162254.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,,EXT*00", h); err != nil {
+		t.Fatalf("Error parsing GGA: %v", err)
+	}
+	if !reflect.DeepEqual(h.rmc.ExtraFields, []string{"V"}) || h.rmc.Mode != Autonomous {
+		t.Errorf("Expected the RMC's extra field, got %#v", h.rmc)
+	}
+	if !reflect.DeepEqual(h.gga.ExtraFields, []string{"EXT"}) {
+		t.Errorf("Expected the GGA's extra field, got %#v", h.gga)
+	}
+}
+
 func TestRMCBadTime(t *testing.T) {
 	input := "$GPRMC,262254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74"
 	h := &rmcHandler{}
-	if err := rmcParser(strings.Split(input, ","), h); err == nil {
+	if err := rmcParser(&Processor{}, strings.Split(input, ","), h); err == nil {
 		t.Errorf("Expected error parsing bad time")
 	}
 }
@@ -345,7 +569,7 @@ func (r *vtgHandler) HandleVTG(vtg VTG) {
 
 func TestVTGError(t *testing.T) {
 	h := &vtgHandler{}
-	err := vtgParser([]string{"VTG", "x", "T", "x", "M", "x", "N", "x", "K"}, h)
+	err := vtgParser(&Processor{}, []string{"VTG", "x", "T", "x", "M", "x", "N", "x", "K"}, h)
 	if err == nil {
 		t.Errorf("Expected error parsing garbage")
 	}
@@ -354,19 +578,79 @@ func TestVTGError(t *testing.T) {
 func TestVTGHandling(t *testing.T) {
 	h := &vtgHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := VTG{
 		True:     188.36,
 		Magnetic: 0,
 		Knots:    0.82,
 		KMH:      1.519,
+		Mode:     Autonomous,
+		Valid:    true,
 	}
 	if !similar(t, h.vtg, exp) {
 		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.vtg, exp)
 	}
 }
 
+func TestVTGValid(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"$GPVTG,,T,,M,0.00,N,0.00,K,A*23", true},
+		{"$GPVTG,,T,,M,,N,,K,N*2C", false},
+		{"$GPVTG,,T,,M,,N,,K*4E", false},
+		{"$GPVTG,,T,,M,0.00,N,0.00,K,N*2C", false},
+	}
+	for _, test := range tests {
+		h := &vtgHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.vtg.Valid != test.valid || h.vtg.Knots != 0 {
+			t.Errorf("On %q, expected valid=%v and zero speed, got %+v", test.in, test.valid, h.vtg)
+		}
+	}
+}
+
+func TestVTGSpeedMismatch(t *testing.T) {
+	const good = "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48"
+	const bad = "$GPVTG,054.7,T,034.4,M,005.5,N,012.2,K*4A"
+
+	var mismatched []VTG
+	p := &Processor{OnSpeedMismatch: func(v VTG) { mismatched = append(mismatched, v) }}
+	for _, in := range []string{good, bad} {
+		h := &vtgHandler{}
+		if err := p.parseMessage(in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", in, err)
+		}
+		if h.vtg.Knots != 5.5 {
+			t.Errorf("Expected %q to be handled, got %+v", in, h.vtg)
+		}
+	}
+	if len(mismatched) != 1 || mismatched[0].KMH != 12.2 {
+		t.Errorf("Expected only the inconsistent VTG reported, got %+v", mismatched)
+	}
+
+	h := &vtgHandler{}
+	p = &Processor{Strict: true}
+	if err := p.parseMessage(good, h); err != nil {
+		t.Errorf("Error parsing %q strictly: %v", good, err)
+	}
+	err := p.parseMessage(bad, h)
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected a ParseError for %q in strict mode, got %v", bad, err)
+	}
+
+	// A looser tolerance accepts it.
+	p.SpeedTolerance = 1.5
+	if err := p.parseMessage(bad, h); err != nil {
+		t.Errorf("Error parsing %q with a loose tolerance: %v", bad, err)
+	}
+}
+
 type ggaHandler struct {
 	gga GGA
 }
@@ -375,6 +659,14 @@ func (g *ggaHandler) HandleGGA(gga GGA) {
 	g.gga = gga
 }
 
+type ggaRecorder struct {
+	f func(GGA)
+}
+
+func (g *ggaRecorder) HandleGGA(gga GGA) {
+	g.f(gga)
+}
+
 func TestFixQualityStringing(t *testing.T) {
 	got := fmt.Sprint(FloatRealTimeKinematicFix)
 	if got != "float rt kinematic" {
@@ -385,7 +677,7 @@ func TestFixQualityStringing(t *testing.T) {
 func TestGGAHandling(t *testing.T) {
 	h := &ggaHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := GGA{
 		Taken:              time.Date(0, 1, 1, 16, 22, 54, 0, time.UTC),
@@ -402,9 +694,36 @@ func TestGGAHandling(t *testing.T) {
 	}
 }
 
+func TestGGAEllipsoidalAltitude(t *testing.T) {
+	h := &ggaHandler{}
+	for _, s := range strings.Split(ubloxSample, "\n") {
+		(&Processor{}).parseMessage(s, h)
+	}
+	if got := h.gga.EllipsoidalAltitude(); !near(got, 500) {
+		t.Errorf("Expected ellipsoidal altitude 500, got %v", got)
+	}
+	if got := (GGA{Altitude: 545.4, GeoidHeight: 46.9}).EllipsoidalAltitude(); !near(got, 592.3) {
+		t.Errorf("Expected ellipsoidal altitude 592.3, got %v", got)
+	}
+}
+
+func TestGGANegativeAltitude(t *testing.T) {
+	// Dead Sea shore, well below sea level.
+	h := &ggaHandler{}
+	if err := (&Processor{}).parseMessage("$GPGGA,123519,3128.000,N,03530.000,E,1,08,0.9,-412.0,M,19.2,M,,*63", h); err != nil {
+		t.Fatalf("Error parsing: %v", err)
+	}
+	if !near(h.gga.Altitude, -412) {
+		t.Errorf("Expected altitude -412, got %v", h.gga.Altitude)
+	}
+	if got := h.gga.EllipsoidalAltitude(); !near(got, -392.8) {
+		t.Errorf("Expected ellipsoidal altitude -392.8, got %v", got)
+	}
+}
+
 func TestGGAGonnaHaveABadTime(t *testing.T) {
 	h := &ggaHandler{}
-	err := ggaParser([]string{"$GPGGA", "999999", "4807.038", "N", "01131.000", "E", "1",
+	err := ggaParser(&Processor{}, []string{"$GPGGA", "999999", "4807.038", "N", "01131.000", "E", "1",
 		"08", "0.9", "545.4", "M", "46.9", "M", "", "", "*44"}, h)
 	if err == nil {
 		t.Errorf("Expected error parsing invalid time, got %v", h.gga)
@@ -422,7 +741,7 @@ func (g *gsaHandler) HandleGSA(gsa GSA) {
 func TestGSAHandling(t *testing.T) {
 	h := &gsaHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := GSA{
 		Auto:     true,
@@ -437,6 +756,56 @@ func TestGSAHandling(t *testing.T) {
 	}
 }
 
+func TestGSASystemID(t *testing.T) {
+	tests := []struct {
+		in  string
+		exp int
+	}{
+		{"$GNGSA,A,3,65,66,,,,,,,,,,,2.56,2.36,1.00*18", 0},
+		{"$GNGSA,A,3,65,66,,,,,,,,,,,2.56,2.36,1.00,2*06", 2},
+	}
+	for _, test := range tests {
+		h := &gsaHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.gsa.SystemID != test.exp || len(h.gsa.SatsUsed) != 2 || h.gsa.VDOP != 1 {
+			t.Errorf("On %q, expected system %v, got %+v", test.in, test.exp, h.gsa)
+		}
+	}
+}
+
+func TestGSAUsable(t *testing.T) {
+	tests := []struct {
+		fix           GSAFix
+		pdop          float64
+		hasFix, has3D bool
+		usable        bool
+	}{
+		{NoFix, 1.5, false, false, false},
+		{Fix2D, 1.5, true, false, true},
+		{Fix2D, 8, true, false, false},
+		{Fix3D, 1.5, true, true, true},
+		{Fix3D, 5, true, true, true},
+		{Fix3D, 5.1, true, true, false},
+		{Fix3D, 0, true, true, false},
+		{0, 1.5, false, false, false},
+	}
+	for _, test := range tests {
+		if got := test.fix.HasFix(); got != test.hasFix {
+			t.Errorf("%v.HasFix() = %v, want %v", test.fix, got, test.hasFix)
+		}
+		if got := test.fix.Has3D(); got != test.has3D {
+			t.Errorf("%v.Has3D() = %v, want %v", test.fix, got, test.has3D)
+		}
+		g := GSA{Fix: test.fix, PDOP: test.pdop}
+		if got := g.Usable(5); got != test.usable {
+			t.Errorf("%v with PDOP %v: Usable(5) = %v, want %v", test.fix, test.pdop, got, test.usable)
+		}
+	}
+}
+
 type gllHandler struct {
 	gll GLL
 }
@@ -448,27 +817,123 @@ func (g *gllHandler) HandleGLL(gll GLL) {
 func TestGLLHandling(t *testing.T) {
 	h := &gllHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := GLL{
 		Latitude:  37.383806166666666,
 		Longitude: -121.9899755,
 		Active:    true,
 		Taken:     time.Date(0, 1, 1, 16, 22, 54, 0, time.UTC),
+		Mode:      Autonomous,
 	}
 	if !similar(t, h.gll, exp) {
 		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.gll, exp)
 	}
 }
 
+func TestGLLMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode Mode
+	}{
+		{"$GPGLL,3723.02837,N,12159.39853,W,162254.00,A,A*7C", Autonomous},
+		{"$GPGLL,3723.02837,N,12159.39853,W,162254.00,A,D*79", Differential},
+		{"$GPGLL,1746.690,N,15219.254,W,054707.559,V*3F", 0},
+	}
+
+	for _, test := range tests {
+		h := &gllHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if h.gll.Mode != test.mode {
+			t.Errorf("On %q, expected mode %v, got %v", test.in, test.mode, h.gll.Mode)
+		}
+	}
+}
+
+func TestModes(t *testing.T) {
+	tests := []struct {
+		in     rune
+		exp    Mode
+		name   string
+		experr bool
+	}{
+		{'A', Autonomous, "autonomous", false},
+		{'D', Differential, "differential", false},
+		{'E', Estimated, "estimated", false},
+		{'F', FloatRTK, "float rtk", false},
+		{'M', Manual, "manual", false},
+		{'N', NotValid, "not valid", false},
+		{'P', Precise, "precise", false},
+		{'R', RTK, "rtk", false},
+		{'S', Simulator, "simulator", false},
+		{'X', 0, "unspecified", true},
+		{0, 0, "unspecified", true},
+	}
+	for _, test := range tests {
+		m, err := ParseMode(test.in)
+		if m != test.exp || (err != nil) != test.experr {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v, error=%v", test.in, m, err, test.exp, test.experr)
+		}
+		if m.String() != test.name {
+			t.Errorf("%q.String() = %q, want %q", test.in, m.String(), test.name)
+		}
+	}
+
+	if got := Mode('X').String(); got != `[Invalid Mode: 'X']` {
+		t.Errorf("Unexpected name for invalid mode: %v", got)
+	}
+}
+
+func TestModeParsing(t *testing.T) {
+	in := "$GPVTG,188.36,T,,M,0.820,N,1.519,K,X*26"
+	h := &vtgHandler{}
+	if err := (&Processor{}).parseMessage(in, h); err != nil {
+		t.Errorf("Unexpected error parsing unknown mode leniently: %v", err)
+	}
+	if h.vtg.Mode != 'X' {
+		t.Errorf("Expected unknown mode to be kept, got %v", h.vtg.Mode)
+	}
+	if err := (&Processor{Strict: true}).parseMessage(in, h); err == nil {
+		t.Errorf("Expected error parsing unknown mode strictly")
+	}
+}
+
 func TestGLLGonnaHaveABadTime(t *testing.T) {
 	h := &gllHandler{}
-	err := gllParser([]string{"$GPGLL", "4916.46", "N", "12311.12", "W", "999999", "A", "*44"}, h)
+	err := gllParser(&Processor{}, []string{"$GPGLL", "4916.46", "N", "12311.12", "W", "999999", "A", "*44"}, h)
 	if err == nil {
 		t.Errorf("Expected error parsing invalid time, got %#v", h.gll)
 	}
 }
 
+func TestBadHemisphere(t *testing.T) {
+	tests := []string{
+		"$GPGLL,4916.45,Q,12311.12,W,225444,A*2E",
+		"$GPGGA,162254.00,3723.02837,N,12159.39853,Q,1,03,2.36,525.6,M,-25.6,M,,*63",
+	}
+	for _, in := range tests {
+		err := (&Processor{}).parseMessage(in, &testUnion{})
+		if pe, ok := err.(*ParseError); !ok || pe.Field != "hemisphere" || pe.Value != "Q" {
+			t.Errorf("Expected hemisphere ParseError on %q, got %v", in, err)
+		}
+	}
+
+	h := &gllHandler{}
+	if err := (&Processor{}).parseMessage("$GPGLL,4916.45,N,12311.12,W,225444,A*31", h); err != nil {
+		t.Fatalf("Error parsing valid GLL: %v", err)
+	}
+	if !near(h.gll.Latitude, 49.274166) || !near(h.gll.Longitude, -123.185333) {
+		t.Errorf("Unexpected position %v,%v", h.gll.Latitude, h.gll.Longitude)
+	}
+
+	if err := (&Processor{}).parseMessage("$GPGLL,,,,,225444,V*07", h); err != nil {
+		t.Errorf("Unexpected error parsing GLL without a fix: %v", err)
+	}
+}
+
 type zdaHandler struct {
 	zda ZDA
 }
@@ -481,7 +946,7 @@ func (g *zdaHandler) HandleZDA(zda ZDA) {
 func TestZDAHandling(t *testing.T) {
 	h := &zdaHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 	exp := ZDA{time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC)}
 	if !similar(t, h.zda, exp) {
@@ -498,7 +963,7 @@ func TestZDAZones(t *testing.T) {
 
 	for in, exp := range tests {
 		h := &zdaHandler{}
-		parseMessage(in, h)
+		(&Processor{}).parseMessage(in, h)
 		if !similar(t, h.zda, ZDA{exp}) {
 			t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.zda, exp)
 		}
@@ -506,6 +971,35 @@ func TestZDAZones(t *testing.T) {
 	}
 }
 
+func TestZDALocal(t *testing.T) {
+	tests := []struct {
+		in         string
+		local, utc string
+		str        string
+	}{
+		{"$GPZDA,110003.00,27,03,2006,-5,00*7f", "06:00:03 -0500", "11:00:03 +0000",
+			"2006-03-27T06:00:03-05:00 (2006-03-27T11:00:03Z)"},
+		{"$GPZDA,162254.00,11,07,2006,00,00*63", "16:22:54 +0000", "16:22:54 +0000",
+			"2006-07-11T16:22:54Z"},
+	}
+	for _, test := range tests {
+		h := &zdaHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if got := h.zda.Local().Format("15:04:05 -0700"); got != test.local {
+			t.Errorf("On %q, expected local time %v, got %v", test.in, test.local, got)
+		}
+		if got := h.zda.UTC().Format("15:04:05 -0700"); got != test.utc {
+			t.Errorf("On %q, expected UTC time %v, got %v", test.in, test.utc, got)
+		}
+		if got := h.zda.String(); got != test.str {
+			t.Errorf("On %q, expected %q, got %q", test.in, test.str, got)
+		}
+	}
+}
+
 type gsvHandler struct {
 	gsv GSV
 }
@@ -592,6 +1086,56 @@ func TestGSVAccumulation(t *testing.T) {
 	}
 }
 
+func TestGSVAccumulationPartial(t *testing.T) {
+	one := GSV{TotalSentences: 3, SentenceNum: 1, InView: 9, SatInfo: []GSVSatInfo{
+		{25, 15, 175, 30},
+		{14, 80, 41, 0},
+		{19, 38, 259, 14},
+		{1, 52, 233, 18},
+	}}
+	two := GSV{TotalSentences: 3, SentenceNum: 2, InView: 9, SatInfo: []GSVSatInfo{
+		{18, 16, 79, 0},
+		{11, 19, 312, 0},
+		{21, 4, 135, 25},
+		{15, 27, 134, 18},
+	}}
+	three := GSV{TotalSentences: 3, SentenceNum: 3, InView: 9, SatInfo: []GSVSatInfo{
+		{3, 25, 222, 0},
+	}}
+
+	var partials [][]GSVSatInfo
+	a := GSVAccumulator{Partial: func(inView int, sats []GSVSatInfo) {
+		if inView != 9 {
+			t.Errorf("Expected 9 in view, got %v", inView)
+		}
+		partials = append(partials, sats)
+	}}
+
+	// The final sentence of the first set is lost.
+	for _, g := range []GSV{one, two, one, two, three} {
+		a.Add(g)
+	}
+	if len(partials) != 1 || len(partials[0]) != 8 {
+		t.Fatalf("Expected one partial flush of 8 satellites, got %v", partials)
+	}
+
+	// A complete set isn't flushed.
+	a.Flush()
+	if len(partials) != 1 {
+		t.Errorf("Unexpected flush of a complete set: %v", partials)
+	}
+
+	a.Add(one)
+	a.Flush()
+	if len(partials) != 2 || len(partials[1]) != 4 {
+		t.Errorf("Expected a partial flush of 4 satellites, got %v", partials)
+	}
+	a.Flush()
+	if len(partials) != 2 {
+		t.Errorf("Unexpected second flush: %v", partials)
+	}
+}
+
 type gsvAccStreamer struct {
 	g        GSVAccumulator
 	complete bool
@@ -646,7 +1190,7 @@ func TestStreamAccumulation(t *testing.T) {
 func TestGSVHandling(t *testing.T) {
 	h := &gsvHandler{}
 	for _, s := range strings.Split(ubloxSample, "\n") {
-		parseMessage(s, h)
+		(&Processor{}).parseMessage(s, h)
 	}
 
 	exp := GSV{
@@ -663,6 +1207,158 @@ func TestGSVHandling(t *testing.T) {
 	}
 }
 
+func TestGSVTruncation(t *testing.T) {
+	four := []GSVSatInfo{{1, 40, 83, 46}, {2, 17, 308, 41}, {12, 7, 344, 39}, {14, 22, 228, 45}}
+	two := four[:2]
+	tests := []struct {
+		in        string
+		sats      []GSVSatInfo
+		signal    int
+		truncated bool
+	}{
+		{"$GPGSV,2,1,08,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45*75", four, 0, false},
+		{"$GPGSV,2,2,06,01,40,083,46,02,17,308,41*79", two, 0, false},
+		{"$GPGSV,2,1,08,01,40,083,46,02,17,308,41,12*5B", two, 0, true},
+		{"$GPGSV,2,1,08,01,40,083,46,02,17,308,41,12,07,344,39,14,22,228,45,1*68", four, 1, false},
+		{"$GPGSV,2,2,06,01,40,083,46,02,17,308,41,7*62", two, 7, false},
+	}
+
+	for _, test := range tests {
+		h := &gsvHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil {
+			t.Errorf("Error parsing %q: %v", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(h.gsv.SatInfo, test.sats) {
+			t.Errorf("On %q, expected satellites %v, got %v", test.in, test.sats, h.gsv.SatInfo)
+		}
+		if h.gsv.SignalID != test.signal || h.gsv.Truncated != test.truncated {
+			t.Errorf("On %q, expected signal %v, truncated %v, got %v, %v",
+				test.in, test.signal, test.truncated, h.gsv.SignalID, h.gsv.Truncated)
+		}
+	}
+}
+
+func TestStrictGSVRange(t *testing.T) {
+	tests := []struct {
+		in    string
+		field string
+	}{
+		{"$GPGSV,2,2,06,01,40,083,46,02,17,308,41*79", ""},
+		{"$GPGSV,2,2,06,01,95,083,46,02,17,308,41*71", "elevation"},
+		{"$GPGSV,2,2,06,01,-5,083,46,02,17,308,41*65", "elevation"},
+		{"$GPGSV,2,2,06,01,40,360,46,02,17,308,41*77", "azimuth"},
+	}
+	for _, test := range tests {
+		h := &gsvHandler{}
+		if err := (&Processor{}).parseMessage(test.in, h); err != nil || len(h.gsv.SatInfo) != 2 {
+			t.Errorf("Expected %q to parse leniently, got %v, %+v", test.in, err, h.gsv)
+		}
+
+		h = &gsvHandler{}
+		err := (&Processor{Strict: true}).parseMessage(test.in, h)
+		if test.field == "" {
+			if err != nil {
+				t.Errorf("Error parsing %q strictly: %v", test.in, err)
+			}
+			continue
+		}
+		if pe, ok := err.(*ParseError); !ok || pe.Field != test.field {
+			t.Errorf("Expected an invalid %v in %q, got %v", test.field, test.in, err)
+		}
+		if h.gsv.SatInfo != nil {
+			t.Errorf("Expected %q to be rejected, got %+v", test.in, h.gsv)
+		}
+	}
+}
+
+func TestConstellation(t *testing.T) {
+	tests := []struct {
+		talker string
+		prn    int
+		exp    Constellation
+	}{
+		{"GP", 12, GPSConstellation},
+		{"GP", 46, SBASConstellation},
+		{"GN", 133, SBASConstellation},
+		{"GN", 70, GLONASSConstellation},
+		{"GN", 193, QZSSConstellation},
+		{"GN", 305, GalileoConstellation},
+		{"GN", 410, BeiDouConstellation},
+		{"GN", 99, UnknownConstellation},
+		{"GL", 70, GLONASSConstellation},
+		{"GA", 12, GalileoConstellation},
+		{"GB", 12, BeiDouConstellation},
+		{"BD", 12, BeiDouConstellation},
+		{"", 12, GPSConstellation},
+	}
+	for _, test := range tests {
+		if got := (GSVSatInfo{PRN: test.prn}).Constellation(test.talker); got != test.exp {
+			t.Errorf("PRN %v from %q: expected %v, got %v", test.prn, test.talker, test.exp, got)
+		}
+	}
+
+	if got := fmt.Sprint(GalileoConstellation); got != "Galileo" {
+		t.Errorf("Expected Galileo, got %q", got)
+	}
+	if got := fmt.Sprint(Constellation(99)); got != "[Invalid Constellation: 99]" {
+		t.Errorf("Unexpected invalid constellation string: %q", got)
+	}
+}
+
+type gstHandler struct {
+	gst GST
+}
+
+func (g *gstHandler) HandleGST(m GST) { g.gst = m }
+
+func TestGSTHandling(t *testing.T) {
+	const gst = "$GPGST,024603.25,3.2,6.6,4.7,47.3,5.8,5.6,22.0*5F\n"
+	tests := []struct {
+		name, in string
+		exp      time.Time
+	}{
+		{"undated", gst, time.Date(0, 1, 1, 2, 46, 3, 250000000, time.UTC)},
+		{"dated", "$GPZDA,024602.00,11,07,2006,00,00*67\n" + gst,
+			time.Date(2006, 7, 11, 2, 46, 3, 250000000, time.UTC)},
+		{"midnight", "$GPZDA,235959.00,10,07,2006,00,00*65\n" + gst,
+			time.Date(2006, 7, 11, 2, 46, 3, 250000000, time.UTC)},
+		// 23:59:58 UTC, in a zone 13 hours east, where it's the
+		// following morning.
+		{"zoned", "$GPZDA,235958.00,27,03,2006,13,00*66\n$GPGST,235959.00,1.8,,,,1.7,1.3,2.2*75\n",
+			time.Date(2006, 3, 27, 23, 59, 59, 0, time.UTC)},
+		// Closer to the following morning than that morning.
+		{"rmc", "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n" + gst,
+			time.Date(2006, 7, 12, 2, 46, 3, 250000000, time.UTC)},
+	}
+	for _, test := range tests {
+		h := &gstHandler{}
+		if err := Process(strings.NewReader(test.in), h, func(s string, e error) error { return e }); err != nil {
+			t.Errorf("%v: error processing: %v", test.name, err)
+			continue
+		}
+		if !h.gst.Taken.Equal(test.exp) {
+			t.Errorf("%v: expected %v, got %v", test.name, test.exp, h.gst.Taken)
+		}
+	}
+
+	h := &gstHandler{}
+	Process(strings.NewReader(gst), h, nil)
+	exp := GST{
+		Taken:       h.gst.Taken,
+		RMS:         3.2,
+		SemiMajor:   6.6,
+		SemiMinor:   4.7,
+		Orientation: 47.3,
+		LatError:    5.8,
+		LonError:    5.6,
+		AltError:    22,
+	}
+	if h.gst != exp {
+		t.Errorf("Expected %+v, got %+v", exp, h.gst)
+	}
+}
+
 func TestDefaultErrorHandler(t *testing.T) {
 	e := defaultErrorHandler("doing x", errors.New("x"))
 	if e != nil {
@@ -682,3 +1378,46 @@ func TestNonDefaultErrorHandler(t *testing.T) {
 		t.Errorf("Expected error parsing junk, got nil")
 	}
 }
+
+func TestStrictCoordinateRange(t *testing.T) {
+	tests := []struct {
+		lat, latRef, lon, lonRef string
+		strict, experr           bool
+	}{
+		{"4807.038", "N", "01131.000", "E", false, false},
+		{"4807.038", "N", "01131.000", "E", true, false},
+		{"9907.038", "N", "01131.000", "E", false, false},
+		{"9907.038", "N", "01131.000", "E", true, true},
+		{"9907.038", "S", "01131.000", "E", true, true},
+		{"4807.038", "N", "20031.000", "W", true, true},
+		{"9000.000", "N", "18000.000", "W", true, false},
+		{"372.02837", "N", "12159.39853", "W", false, false},
+		{"372.02837", "N", "12159.39853", "W", true, true},
+		{"3723.02837", "N", "2159.39853", "W", true, true},
+		{"3723", "N", "12159", "W", true, false},
+	}
+
+	for _, test := range tests {
+		p := &Processor{Strict: test.strict}
+
+		rh := &rmcHandler{}
+		err := rmcParser(p, []string{"$GPRMC", "123519", "A", test.lat, test.latRef,
+			test.lon, test.lonRef, "022.4", "084.4", "230394", "003.1", "W"}, rh)
+		if (err != nil) != test.experr {
+			t.Errorf("RMC %+v: expected error=%v, got %v", test, test.experr, err)
+		}
+		if _, ok := err.(*ParseError); err != nil && !ok {
+			t.Errorf("RMC %+v: expected a ParseError, got %T", test, err)
+		}
+
+		gh := &ggaHandler{}
+		err = ggaParser(p, []string{"$GPGGA", "123519", test.lat, test.latRef, test.lon, test.lonRef,
+			"1", "08", "0.9", "545.4", "M", "46.9", "M", "", ""}, gh)
+		if (err != nil) != test.experr {
+			t.Errorf("GGA %+v: expected error=%v, got %v", test, test.experr, err)
+		}
+		if err != nil && gh.gga.Latitude != 0 {
+			t.Errorf("GGA %+v: handler was called with an invalid fix: %#v", test, gh.gga)
+		}
+	}
+}
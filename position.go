@@ -0,0 +1,70 @@
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Position is a point on the earth in decimal degrees, north and east
+// positive.
+type Position struct {
+	Lat, Lon float64
+}
+
+// DistanceTo returns the great-circle distance to o.
+func (p Position) DistanceTo(o Position) Meters {
+	return Distance(p.Lat, p.Lon, o.Lat, o.Lon)
+}
+
+// BearingTo returns the initial great-circle bearing in degrees
+// ([0,360)) to o.
+func (p Position) BearingTo(o Position) float64 {
+	return Bearing(p.Lat, p.Lon, o.Lat, o.Lon)
+}
+
+// Equal reports whether o is within tolerance of p.
+func (p Position) Equal(o Position, tolerance Meters) bool {
+	return p.DistanceTo(o) <= tolerance
+}
+
+// String formats p in degrees, minutes and seconds, e.g.
+// 37°23'01.7"N 121°59'23.9"W.
+func (p Position) String() string {
+	return dmsString(p.Lat, "NS") + " " + dmsString(p.Lon, "EW")
+}
+
+// FormatDegrees formats a coordinate in decimal degrees with the
+// given number of decimal places, e.g. "-121.989976" for six.  Six
+// places resolve about ten centimeters, finer than most fixes.
+func FormatDegrees(deg float64, precision int) string {
+	return strconv.FormatFloat(deg, 'f', precision, 64)
+}
+
+func dmsString(deg float64, refs string) string {
+	ref := refs[0]
+	if deg < 0 {
+		ref = refs[1]
+		deg = -deg
+	}
+	// Round to tenths of a second up front so rounding can't
+	// produce 60 seconds.
+	tenths := int64(math.Round(deg * 36000))
+	return fmt.Sprintf(`%d°%02d'%04.1f"%c`,
+		tenths/36000, tenths/600%60, float64(tenths%600)/10, ref)
+}
+
+// Position returns the position of the fix.
+func (r RMC) Position() Position {
+	return Position{r.Latitude, r.Longitude}
+}
+
+// Position returns the position of the fix.
+func (g GGA) Position() Position {
+	return Position{g.Latitude, g.Longitude}
+}
+
+// Position returns the position of the fix.
+func (g GLL) Position() Position {
+	return Position{g.Latitude, g.Longitude}
+}
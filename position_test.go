@@ -0,0 +1,70 @@
+package nmea
+
+import "testing"
+
+func TestPosition(t *testing.T) {
+	home := Position{37.383806, -121.989975}
+	sf := Position{37.774929, -122.419416}
+
+	if got := home.DistanceTo(sf); got-57650.50396 > 0.01 || 57650.50396-got > 0.01 {
+		t.Errorf("Expected distance of 57650.50m, got %v", got)
+	}
+	if got := (Position{0, 0}).BearingTo(Position{0, 1}); !near(got, 90) {
+		t.Errorf("Expected bearing of 90, got %v", got)
+	}
+	if !home.Equal(Position{37.383807, -121.989975}, 1) {
+		t.Errorf("Expected nearby positions to be equal")
+	}
+	if home.Equal(sf, 1000) {
+		t.Errorf("Expected distant positions to differ")
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		in  Position
+		exp string
+	}{
+		{Position{37.383806, -121.989975}, `37°23'01.7"N 121°59'23.9"W`},
+		{Position{-33.856784, 151.215297}, `33°51'24.4"S 151°12'55.1"E`},
+		{Position{0, 0}, `0°00'00.0"N 0°00'00.0"E`},
+		{Position{10.999999, 0}, `11°00'00.0"N 0°00'00.0"E`},
+	}
+	for _, test := range tests {
+		if got := test.in.String(); got != test.exp {
+			t.Errorf("%#v.String() = %s, want %s", test.in, got, test.exp)
+		}
+	}
+}
+
+func TestSentencePosition(t *testing.T) {
+	exp := Position{37.383806, -121.989975}
+	for _, p := range []Position{
+		RMC{Latitude: 37.383806, Longitude: -121.989975}.Position(),
+		GGA{Latitude: 37.383806, Longitude: -121.989975}.Position(),
+		GLL{Latitude: 37.383806, Longitude: -121.989975}.Position(),
+	} {
+		if p != exp {
+			t.Errorf("Expected %v, got %v", exp, p)
+		}
+	}
+}
+
+func TestFormatDegrees(t *testing.T) {
+	tests := []struct {
+		in   float64
+		prec int
+		exp  string
+	}{
+		{37.383806166666666, 6, "37.383806"},
+		{-121.9899761, 6, "-121.989976"},
+		{-121.9899755, 3, "-121.990"},
+		{-121.9899755, 0, "-122"},
+		{0.5, 2, "0.50"},
+	}
+	for _, test := range tests {
+		if got := FormatDegrees(test.in, test.prec); got != test.exp {
+			t.Errorf("FormatDegrees(%v, %v) = %q, want %q", test.in, test.prec, got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,785 @@
+package nmea
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Processor parses NMEA streams according to its configuration.
+//
+// The zero value is ready to use and behaves exactly like Process.
+//
+// A Processor reuses its parsing buffers from one sentence to the
+// next, so it must only parse one stream at a time.  Applications
+// handling many streams concurrently should give each goroutine its
+// own Processor, perhaps from NewProcessor.  Stats may be called
+// from any goroutine.
+type Processor struct {
+	// Strict enables additional validation of parsed values that
+	// can catch corruption surviving the checksum, such as
+	// coordinates beyond the poles or the antimeridian.
+	Strict bool
+
+	// SpeedTolerance is how far, in knots, the speeds in knots and
+	// km/h of a VTG may disagree before it's considered corrupt.
+	// The default is 0.1 knots, allowing for rounding.
+	SpeedTolerance float64
+	// OnSpeedMismatch, if not nil, is called with each VTG whose
+	// speeds disagree.  In strict mode such sentences are instead
+	// rejected with a ParseError.
+	OnSpeedMismatch func(VTG)
+
+	// RejectQualities lists fix qualities that are withheld from
+	// the handler.  GGA fixes are judged by their quality and RMC
+	// fixes by their mode indicator, if present.  By default all
+	// fixes are delivered.
+	RejectQualities []FixQuality
+	// OnReject, if not nil, is called with each withheld GGA or RMC.
+	OnReject func(fix interface{})
+
+	// MaxLength is the length beyond which lines are rejected
+	// with ErrTooLong without being parsed.  The length includes
+	// the CRLF terminator, even if the line had none.  The zero
+	// value means no limit.  Standard sentences are no longer
+	// than MaxSentenceLength, so set it to MaxSentenceLength to
+	// enforce the standard; longer lines usually result from
+	// corruption, but some proprietary sentences exceed it.
+	MaxLength int
+
+	// RejectEarly rejects RMC and ZDA sentences dated before
+	// EarliestTime with ErrEarlyTime, as receivers without time
+	// sync may report dates near the GPS epoch of 1980.
+	RejectEarly bool
+	// EarliestTime is the earliest plausible date.  The default is
+	// 2000-01-01.
+	EarliestTime time.Time
+
+	// DropUntimed drops sentences without a usable timestamp, such
+	// as GSV, when a TimeWindow is set.  By default they pass
+	// through.
+	DropUntimed bool
+
+	// StopWhen, if not nil, is checked after each sentence.  Once
+	// it returns true, processing stops with ErrStopped, without
+	// flushing the handler.  With a FixReady's Done, for example,
+	// processing stops at the first usable fix, and Elapsed is then
+	// the time to first fix.
+	StopWhen func() bool
+
+	// SplitFunc, if not nil, splits the input of Process,
+	// ProcessLog and ProcessMulti into sentences, for transports
+	// framing them other than with line terminators.  The default
+	// is bufio.ScanLines, accepting LF or CRLF terminators.
+	SplitFunc bufio.SplitFunc
+
+	// BestEffort keeps processing after the error handler returns
+	// an error.  All such errors are returned together (see
+	// errors.Join) once the input is exhausted.
+	BestEffort bool
+
+	// MagneticVariation, if not nil, supplies the magnetic
+	// variation (in degrees, positive east) of RMC fixes not
+	// reporting one, typically from a magnetic model such as the
+	// WMM.
+	MagneticVariation func(lat, lon float64, t time.Time) float64
+
+	// OnSentence, if not nil, is called with the type (e.g. "RMC")
+	// of every sentence parsed without error.
+	OnSentence func(typ string)
+	// OnTiming, if not nil, is called with the type and parse time
+	// (including the handler's) of every sentence of a known type.
+	OnTiming func(typ string, d time.Duration)
+	// OnError, if not nil, is called for every sentence that fails
+	// to parse.  typ is empty if the type couldn't be determined.
+	OnError func(typ string, err error)
+
+	downsample time.Duration
+	held       map[string]*heldSentence // by type
+	heldSeq    int
+
+	windowed               bool
+	windowStart, windowEnd time.Time
+
+	epoch    time.Duration
+	epochSet bool
+	first    time.Time // of the first fix cycle
+	latest   time.Time // of the latest fix cycle
+	dataID   int
+	stnNext  bool      // the last sentence was an STN
+	dated    time.Time // of the last RMC or ZDA
+
+	sentences, parsed, badChecksum, tooLong, unhandled, failed, dropped atomic.Int64
+
+	mu     sync.Mutex
+	byType map[string]int64
+
+	fields []string
+	cp     cumulativeErrorParser
+}
+
+var processorPool = sync.Pool{
+	New: func() interface{} { return &Processor{} },
+}
+
+// NewProcessor returns a Processor with the default configuration,
+// reusing the buffers of one previously given to Release if
+// possible.  This saves allocations when handling many short
+// streams.
+func NewProcessor() *Processor {
+	return processorPool.Get().(*Processor)
+}
+
+// Release resets p and makes its buffers available to NewProcessor.
+// p must not be used afterwards.
+func (p *Processor) Release() {
+	fields := p.fields[:0]
+	*p = Processor{fields: fields}
+	processorPool.Put(p)
+}
+
+// Stats is a snapshot of a Processor's parse counters.
+type Stats struct {
+	// Sentences is the number of non-empty lines seen.
+	Sentences int64
+	// Parsed is the number of sentences parsed without error.
+	Parsed int64
+	// BadChecksum is the number of lines failing checksum validation.
+	BadChecksum int64
+	// Unhandled is the number of sentences of an unknown type.
+	Unhandled int64
+	// Failed is the number of known sentences that failed to parse.
+	Failed int64
+	// Dropped is the number of sentences skipped by downsampling
+	// or TimeWindow.
+	Dropped int64
+	// TooLong is the number of lines exceeding MaxLength.
+	TooLong int64
+	// ByType counts the sentences parsed without error by type
+	// (e.g. "RMC").
+	ByType map[string]int64
+}
+
+// Stats returns a snapshot of the counters accumulated by this
+// Processor.  It is safe to call concurrently with Process.
+func (p *Processor) Stats() Stats {
+	p.mu.Lock()
+	byType := make(map[string]int64, len(p.byType))
+	for k, v := range p.byType {
+		byType[k] = v
+	}
+	p.mu.Unlock()
+
+	return Stats{
+		Sentences:   p.sentences.Load(),
+		Parsed:      p.parsed.Load(),
+		BadChecksum: p.badChecksum.Load(),
+		Unhandled:   p.unhandled.Load(),
+		Failed:      p.failed.Load(),
+		Dropped:     p.dropped.Load(),
+		TooLong:     p.tooLong.Load(),
+		ByType:      byType,
+	}
+}
+
+func (p *Processor) countType(typ string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.byType == nil {
+		p.byType = map[string]int64{}
+	}
+	p.byType[typ]++
+}
+
+// Downsample limits each sentence type to one sentence per d,
+// keeping the most recent sentence of each window.
+//
+// A window of length d opens with the first sentence of a type, timed
+// by the sentence's own timestamp, and takes in the sentences of that
+// type that follow within d.  Types without a timestamp are timed by
+// their arrival instead.  A timestamp moving backwards (e.g. a time
+// of day crossing midnight) opens a new window.  Sentences are held
+// until their window closes, which is when the next window of their
+// type opens, or when processing ends, so handlers see them late.
+// Only the last of each window is then processed; the others reach
+// no handler, not even a RawHandler or EpochHandler, and are counted
+// in Stats.Dropped.
+//
+// A d of zero disables downsampling.
+func (p *Processor) Downsample(d time.Duration) {
+	p.downsample = d
+	p.held = map[string]*heldSentence{}
+}
+
+// A heldSentence is the latest sentence of a Downsample window.
+type heldSentence struct {
+	line  string
+	start time.Time // of the window
+	seq   int       // the order line arrived in
+}
+
+// downsampled passes a sentence through Downsample, returning the
+// sentence to process in its place, if any.
+func (p *Processor) downsampled(line string) (string, bool) {
+	if !checkChecksum(line) {
+		return line, true
+	}
+	typ := sentenceType(line)
+	t, ok := sentenceTime(typ, p.split(line[:len(line)-3]))
+	if !ok {
+		t = time.Now()
+	}
+	p.heldSeq++
+	h, ok := p.held[typ]
+	if !ok {
+		p.held[typ] = &heldSentence{line, t, p.heldSeq}
+		return "", false
+	}
+	if d := t.Sub(h.start); d >= 0 && d < p.downsample {
+		p.sentences.Add(1)
+		p.dropped.Add(1)
+		h.line, h.seq = line, p.heldSeq
+		return "", false
+	}
+	prev := h.line
+	*h = heldSentence{line, t, p.heldSeq}
+	return prev, true
+}
+
+// TimeWindow limits processing to sentences timed from start up to,
+// but not including, end.  A zero end leaves the window open.
+//
+// RMC and ZDA sentences are timed by their own date, and GGA and GLL
+// by their time of day on the date of the last RMC or ZDA.  Until a
+// date is seen, and for types without a timestamp, sentences are
+// kept unless DropUntimed is set.  Skipped sentences reach no
+// handler, not even a RawHandler, and are counted in Stats.Dropped.
+func (p *Processor) TimeWindow(start, end time.Time) {
+	p.windowed = true
+	p.windowStart, p.windowEnd = start, end
+}
+
+// inWindow reports whether a sentence passes the TimeWindow.
+func (p *Processor) inWindow(typ string, parts []string) bool {
+	t, ok := sentenceTime(typ, parts)
+	if ok && typ != "RMC" && typ != "ZDA" {
+		ok = !p.dated.IsZero()
+		t = p.withDate(t)
+	}
+	if !ok {
+		return !p.DropUntimed
+	}
+	return !t.Before(p.windowStart) && (p.windowEnd.IsZero() || t.Before(p.windowEnd))
+}
+
+// checkEpoch reports whether the sentence starts a new fix cycle,
+// and the time of the sentence if so.
+func (p *Processor) checkEpoch(typ string, parts []string) (time.Time, bool) {
+	if typ != "RMC" && typ != "GGA" {
+		return time.Time{}, false
+	}
+	t, ok := sentenceTime(typ, parts)
+	if !ok {
+		return time.Time{}, false
+	}
+	// Compare times of day since GGA carries no date.
+	tod := timeOfDay(t)
+	changed := !p.epochSet || tod != p.epoch
+	p.epoch, p.epochSet = tod, true
+	return t, changed
+}
+
+// Elapsed returns the time covered by the fix cycles processed so
+// far, from the timestamp of the first to that of the latest.
+func (p *Processor) Elapsed() time.Duration {
+	if p.first.IsZero() {
+		return 0
+	}
+	return timeBetween(p.first, p.latest)
+}
+
+// reject reports whether a fix of the given quality should be
+// withheld, notifying OnReject if so.
+func (p *Processor) reject(q FixQuality, fix interface{}) bool {
+	for _, r := range p.RejectQualities {
+		if q == r {
+			if p.OnReject != nil {
+				p.OnReject(fix)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// speedsAgree reports whether v's speeds in knots and km/h agree
+// within SpeedTolerance.
+func (p *Processor) speedsAgree(v VTG) bool {
+	tolerance := p.SpeedTolerance
+	if tolerance == 0 {
+		tolerance = 0.1
+	}
+	return math.Abs(v.KMH/kmhPerKnot-v.Knots) <= tolerance
+}
+
+func (p *Processor) earliestTime() time.Time {
+	if p.EarliestTime.IsZero() {
+		return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return p.EarliestTime
+}
+
+// withDate returns a time of day on the date of the last RMC or ZDA,
+// assuming it's within 12 hours of that sentence.  The time of day
+// is returned unchanged if no date has been seen.
+func (p *Processor) withDate(tod time.Time) time.Time {
+	if p.dated.IsZero() {
+		return tod
+	}
+	dated := p.dated.UTC()
+	y, m, d := dated.Date()
+	t := time.Date(y, m, d, tod.Hour(), tod.Minute(), tod.Second(), tod.Nanosecond(), time.UTC)
+	switch {
+	case dated.Sub(t) > 12*time.Hour:
+		t = t.AddDate(0, 0, 1)
+	case t.Sub(dated) > 12*time.Hour:
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+func (p *Processor) newParser() *cumulativeErrorParser {
+	p.cp = cumulativeErrorParser{strict: p.Strict}
+	return &p.cp
+}
+
+// split splits a sentence into its comma separated fields, reusing
+// p's buffer.  The result is only valid until the next call.
+func (p *Processor) split(s string) []string {
+	parts := p.fields[:0]
+	for {
+		i := strings.IndexByte(s, ',')
+		if i < 0 {
+			break
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+	parts = append(parts, s)
+	p.fields = parts
+	return parts
+}
+
+// sentenceType makes a best effort to extract the sentence type from
+// a line, even one that fails its checksum.
+//
+// Standard sentences are identified by the three letters following
+// the talker ID (e.g. "RMC").  Proprietary sentences are identified
+// by their whole address, including the leading P and manufacturer
+// code (e.g. "PGRME").  Encapsulation sentences starting with '!',
+// such as AIS, are identified like standard ones.
+func sentenceType(line string) string {
+	if i := strings.IndexAny(line, ",*"); i >= 0 {
+		line = line[:i]
+	}
+	if len(line) > 4 && line[0] == '$' && line[1] == 'P' {
+		return line[1:]
+	}
+	if len(line) < 6 || (line[0] != '$' && line[0] != '!') {
+		return ""
+	}
+	return line[3:]
+}
+
+func (p *Processor) parseMessage(line string, handler interface{}) error {
+	typ := sentenceType(line)
+	err := p.dispatch(typ, line, handler)
+	if err != nil {
+		if p.OnError != nil {
+			p.OnError(typ, err)
+		}
+		return err
+	}
+	if p.OnSentence != nil {
+		p.OnSentence(typ)
+	}
+	return nil
+}
+
+func (p *Processor) dispatch(typ, line string, handler interface{}) error {
+	p.sentences.Add(1)
+	if p.MaxLength > 0 && len(line)+2 > p.MaxLength {
+		p.tooLong.Add(1)
+		return ErrTooLong
+	}
+	if !checkChecksum(line) {
+		// skip bad checksums
+		p.badChecksum.Add(1)
+		return errBadChecksum
+	}
+
+	parts := p.split(line[:len(line)-3])
+
+	inner := unwrap(handler)
+	early := false
+	if typ == "RMC" || typ == "ZDA" {
+		if t, ok := sentenceTime(typ, parts); ok {
+			if early = p.RejectEarly && t.Before(p.earliestTime()); !early {
+				p.dated = t
+			}
+		}
+	}
+	skip := p.windowed && !p.inWindow(typ, parts)
+	if t, ok := p.checkEpoch(typ, parts); ok {
+		// An STN announces the sentence following it, even
+		// when that one starts a new cycle.
+		if !p.stnNext {
+			p.dataID = 0
+		}
+		if p.first.IsZero() {
+			p.first = t
+		}
+		p.latest = t
+		if h, ok := inner.(EpochHandler); ok && !skip {
+			h.HandleEpoch(t)
+		}
+	}
+	p.stnNext = false
+	if skip {
+		p.dropped.Add(1)
+		return nil
+	}
+	if h, ok := handler.(RawHandler); ok {
+		h.HandleRaw(line)
+	}
+	if early {
+		p.failed.Add(1)
+		return ErrEarlyTime
+	}
+	handler = inner
+
+	f, ok := addressParsers[parts[0][1:]]
+	if !ok {
+		f, ok = parsers[typ]
+	}
+	if !ok {
+		p.unhandled.Add(1)
+		return ErrUnhandled
+	}
+	var err error
+	if p.OnTiming != nil {
+		start := time.Now()
+		err = f(p, parts, handler)
+		p.OnTiming(typ, time.Since(start))
+	} else {
+		err = f(p, parts, handler)
+	}
+	if err != nil {
+		p.failed.Add(1)
+		return err
+	}
+	p.parsed.Add(1)
+	p.countType(typ)
+	return nil
+}
+
+// lineScanner is a Scanner noting whether the final line lacked a
+// terminator.
+type lineScanner struct {
+	*bufio.Scanner
+	truncated bool
+}
+
+// newScanner returns a Scanner splitting r with p's SplitFunc.
+// Truncation is only detected with the default SplitFunc.
+func (p *Processor) newScanner(r io.Reader) *lineScanner {
+	s := &lineScanner{Scanner: bufio.NewScanner(r)}
+	if p.SplitFunc != nil {
+		s.Split(p.SplitFunc)
+		return s
+	}
+	s.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if atEOF && token != nil && advance == len(data) && data[len(data)-1] != '\n' {
+			s.truncated = true
+		}
+		return advance, token, err
+	})
+	return s
+}
+
+// scan processes each line from s, as converted by conv.
+func (p *Processor) scan(s *lineScanner, conv func(string) string, handler interface{}, errh ErrorHandler) error {
+	if errh == nil {
+		errh = defaultErrorHandler
+	}
+	var errs []error
+	truncated := false
+	for s.Scan() {
+		lineErrh := errh
+		if s.truncated {
+			lineErrh = func(line string, err error) error {
+				if err == errBadChecksum {
+					truncated = true
+					err = fmt.Errorf("%w: %w", ErrTruncated, err)
+				}
+				return errh(line, err)
+			}
+		}
+		if err := p.processLine(conv(s.Text()), handler, lineErrh, &errs); err != nil {
+			return err
+		}
+	}
+	if err := p.finish(handler, errh, &errs); err != nil {
+		return err
+	}
+	err := s.Err()
+	if err == nil && truncated {
+		err = ErrTruncated
+	}
+	return joinErrors(errs, err)
+}
+
+// processLine parses each sentence in a line, consulting the error
+// handler on failure.  A non-nil return aborts processing.  In
+// BestEffort mode, errors from the error handler are added to errs
+// instead.
+func (p *Processor) processLine(line string, handler interface{}, errh ErrorHandler, errs *[]error) error {
+	for line != "" {
+		var s string
+		s, line = nextSentence(line)
+		if p.downsample > 0 {
+			var ok bool
+			if s, ok = p.downsampled(s); !ok {
+				continue
+			}
+		}
+		if err := p.processSentence(s, handler, errh, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processSentence parses a sentence as processLine does.
+func (p *Processor) processSentence(s string, handler interface{}, errh ErrorHandler, errs *[]error) error {
+	if err := p.parseMessage(s, handler); err != nil {
+		if err = errh(s, err); err != nil {
+			if !p.BestEffort {
+				return err
+			}
+			*errs = append(*errs, err)
+		}
+	}
+	if p.StopWhen != nil && p.StopWhen() {
+		return ErrStopped
+	}
+	return nil
+}
+
+// finish processes the sentences Downsample still holds, in the
+// order they arrived, and then flushes the handler.
+func (p *Processor) finish(handler interface{}, errh ErrorHandler, errs *[]error) error {
+	held := make([]*heldSentence, 0, len(p.held))
+	for typ, h := range p.held {
+		held = append(held, h)
+		delete(p.held, typ)
+	}
+	sort.Slice(held, func(i, j int) bool { return held[i].seq < held[j].seq })
+	for _, h := range held {
+		if err := p.processSentence(h.line, handler, errh, errs); err != nil {
+			return err
+		}
+	}
+	flush(handler)
+	return nil
+}
+
+// nextSentence splits the first sentence from a line that may hold
+// several concatenated sentences, returning it and the remainder.
+func nextSentence(line string) (string, string) {
+	if i := strings.IndexAny(line[1:], "$!"); i >= 0 {
+		return line[:i+1], line[i+1:]
+	}
+	return line, ""
+}
+
+// flush calls Flush on the handler, or the handler it wraps, if it's
+// a Flusher.
+func flush(handler interface{}) {
+	if f, ok := handler.(Flusher); ok {
+		f.Flush()
+	} else if f, ok := unwrap(handler).(Flusher); ok {
+		f.Flush()
+	}
+}
+
+// joinErrors combines the errors collected in BestEffort mode with a
+// final error, if any.
+func joinErrors(errs []error, err error) error {
+	if len(errs) == 0 {
+		return err
+	}
+	return errors.Join(append(errs, err)...)
+}
+
+// Process all of the NMEA messages from the given reader using this
+// Processor's configuration.
+//
+// See the package-level Process for details.
+func (p *Processor) Process(r io.Reader, handler interface{}, errh ErrorHandler) error {
+	return p.scan(p.newScanner(r), func(s string) string { return s }, handler, errh)
+}
+
+// ProcessChan processes NMEA messages from a channel of lines using
+// this Processor's configuration.
+//
+// See the package-level ProcessChan for details.
+func (p *Processor) ProcessChan(lines <-chan string, handler interface{}, errh ErrorHandler) error {
+	if errh == nil {
+		errh = defaultErrorHandler
+	}
+	var errs []error
+	for line := range lines {
+		if err := p.processLine(line, handler, errh, &errs); err != nil {
+			return err
+		}
+	}
+	if err := p.finish(handler, errh, &errs); err != nil {
+		return err
+	}
+	return joinErrors(errs, nil)
+}
+
+// ProcessLog processes a captured log of NMEA messages using this
+// Processor's configuration.
+//
+// See the package-level ProcessLog for details.
+func (p *Processor) ProcessLog(r io.Reader, handler interface{}, errh ErrorHandler) error {
+	return p.scan(p.newScanner(r), unwrapLogLine, handler, errh)
+}
+
+// unwrapLogLine extracts the sentence from a line of a captured log.
+func unwrapLogLine(line string) string {
+	if i := strings.IndexAny(line, "$!"); i >= 0 {
+		return line[i:]
+	}
+	if b, err := hex.DecodeString(strings.Replace(line, " ", "", -1)); err == nil {
+		if s := strings.TrimRight(string(b), "\r\n"); strings.IndexAny(s, "$!") == 0 {
+			return s
+		}
+	}
+	return line
+}
+
+// ProcessMulti processes NMEA messages from several readers
+// concurrently using this Processor's configuration.
+//
+// See the package-level ProcessMulti for details.
+func (p *Processor) ProcessMulti(readers []io.Reader, handler interface{}, errh ErrorHandler) error {
+	if errh == nil {
+		errh = defaultErrorHandler
+	}
+
+	lines := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+	errs := make(chan error, len(readers))
+
+	wg := sync.WaitGroup{}
+	for _, r := range readers {
+		wg.Add(1)
+		go func(r io.Reader) {
+			defer wg.Done()
+			s := p.newScanner(r)
+			for s.Scan() {
+				select {
+				case lines <- s.Text():
+				case <-done:
+					return
+				}
+			}
+			errs <- s.Err()
+		}(r)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+		close(errs)
+	}()
+
+	var perrs []error
+	for line := range lines {
+		if err := p.processLine(line, handler, errh, &perrs); err != nil {
+			return err
+		}
+	}
+	if err := p.finish(handler, errh, &perrs); err != nil {
+		return err
+	}
+	for err := range errs {
+		if err != nil {
+			return joinErrors(perrs, err)
+		}
+	}
+	return joinErrors(perrs, nil)
+}
+
+// lineCapture is a handler for every message type, remembering the
+// last message it handled.
+type lineCapture struct {
+	msg interface{}
+}
+
+func (c *lineCapture) HandleGGA(m GGA)                 { c.msg = m }
+func (c *lineCapture) HandleGLL(m GLL)                 { c.msg = m }
+func (c *lineCapture) HandleGSA(m GSA)                 { c.msg = m }
+func (c *lineCapture) HandleGSV(m GSV)                 { c.msg = m }
+func (c *lineCapture) HandleRMC(m RMC)                 { c.msg = m }
+func (c *lineCapture) HandleRTE(m RTE)                 { c.msg = m }
+func (c *lineCapture) HandleVTG(m VTG)                 { c.msg = m }
+func (c *lineCapture) HandleWPL(m WPL)                 { c.msg = m }
+func (c *lineCapture) HandleXDR(m XDR)                 { c.msg = m }
+func (c *lineCapture) HandleVWR(m VWR)                 { c.msg = m }
+func (c *lineCapture) HandleVWT(m VWT)                 { c.msg = m }
+func (c *lineCapture) HandleDBT(m DBT)                 { c.msg = m }
+func (c *lineCapture) HandleDBK(m DBK)                 { c.msg = m }
+func (c *lineCapture) HandleDBS(m DBS)                 { c.msg = m }
+func (c *lineCapture) HandleTHS(m THS)                 { c.msg = m }
+func (c *lineCapture) HandleAISPosition(m AISPosition) { c.msg = m }
+func (c *lineCapture) HandleXTE(m XTE)                 { c.msg = m }
+func (c *lineCapture) HandleXTC(m XTC)                 { c.msg = m }
+func (c *lineCapture) HandleWCV(m WCV)                 { c.msg = m }
+func (c *lineCapture) HandleSTN(m STN)                 { c.msg = m }
+func (c *lineCapture) HandleMSS(m MSS)                 { c.msg = m }
+func (c *lineCapture) HandleMSK(m MSK)                 { c.msg = m }
+func (c *lineCapture) HandleVDR(m VDR)                 { c.msg = m }
+func (c *lineCapture) HandleRSA(m RSA)                 { c.msg = m }
+func (c *lineCapture) HandleAPA(m APA)                 { c.msg = m }
+func (c *lineCapture) HandleTTM(m TTM)                 { c.msg = m }
+func (c *lineCapture) HandleTLL(m TLL)                 { c.msg = m }
+func (c *lineCapture) HandleGST(m GST)                 { c.msg = m }
+func (c *lineCapture) HandleVPW(m VPW)                 { c.msg = m }
+func (c *lineCapture) HandleZDA(m ZDA)                 { c.msg = m }
+func (c *lineCapture) HandlePGRME(m PGRME)             { c.msg = m }
+func (c *lineCapture) HandlePUBX00(m PUBX00)           { c.msg = m }
+
+// ParseLine parses a single sentence using this Processor's
+// configuration.
+//
+// See the package-level ParseLine for details.
+func (p *Processor) ParseLine(line string) (interface{}, error) {
+	c := &lineCapture{}
+	if err := p.parseMessage(line, c); err != nil {
+		return nil, err
+	}
+	return c.msg, nil
+}
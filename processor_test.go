@@ -0,0 +1,733 @@
+package nmea
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessorStats(t *testing.T) {
+	input := ubloxSample +
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72\n" +
+		"garbage\n" +
+		"\n" +
+		"$GPXXX,1,2,3*53\n" +
+		"$GPGGA,1*4B\n"
+
+	p := &Processor{}
+	if err := p.Process(strings.NewReader(input), &testUnion{}, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := Stats{
+		Sentences:   14,
+		Parsed:      10,
+		BadChecksum: 2,
+		Unhandled:   1,
+		Failed:      1,
+		ByType: map[string]int64{
+			"RMC": 1, "VTG": 1, "GGA": 1, "GSA": 1, "GSV": 4, "GLL": 1, "ZDA": 1,
+		},
+	}
+	if got := p.Stats(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected stats\n%+v\ngot\n%+v", exp, got)
+	}
+}
+
+func TestProcessorHooks(t *testing.T) {
+	input := ubloxSample +
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72\n" +
+		"$GPXXX,1,2,3*53\n"
+
+	var sentences []string
+	errs := map[string]error{}
+	p := &Processor{
+		OnSentence: func(typ string) { sentences = append(sentences, typ) },
+		OnError:    func(typ string, err error) { errs[typ] = err },
+	}
+	if err := p.Process(strings.NewReader(input), nil, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := []string{"RMC", "VTG", "GGA", "GSA", "GSV", "GSV", "GSV", "GSV", "GLL", "ZDA"}
+	if !reflect.DeepEqual(sentences, exp) {
+		t.Errorf("Expected sentences %v, got %v", exp, sentences)
+	}
+
+	experrs := map[string]error{"RMC": errBadChecksum, "XXX": ErrUnhandled}
+	if !reflect.DeepEqual(errs, experrs) {
+		t.Errorf("Expected errors %v, got %v", experrs, errs)
+	}
+}
+
+func TestProcessorTiming(t *testing.T) {
+	input := ubloxSample +
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*72\n" +
+		"$GPXXX,1,2,3*53\n"
+
+	var timed []string
+	p := &Processor{
+		OnTiming: func(typ string, d time.Duration) {
+			if d < 0 {
+				t.Errorf("Negative duration for %v: %v", typ, d)
+			}
+			timed = append(timed, typ)
+		},
+	}
+	if err := p.Process(strings.NewReader(input), &testUnion{}, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := []string{"RMC", "VTG", "GGA", "GSA", "GSV", "GSV", "GSV", "GSV", "GLL", "ZDA"}
+	if !reflect.DeepEqual(timed, exp) {
+		t.Errorf("Expected timings for %v, got %v", exp, timed)
+	}
+}
+
+func TestSentenceType(t *testing.T) {
+	tests := map[string]string{
+		"$GPRMC,1,2*00":  "RMC",
+		"$GPZDA*00":      "ZDA",
+		"$GPGSV":         "GSV",
+		"$GP":            "",
+		"$PUBX,00*00":    "PUBX",
+		"$PGRME,1*00":    "PGRME",
+		"$PGR":           "",
+		"!AIVDM,1,1*00":  "VDM",
+		"garbage,1,2*00": "",
+		"":               "",
+	}
+	for in, exp := range tests {
+		if got := sentenceType(in); got != exp {
+			t.Errorf("sentenceType(%q) = %q, want %q", in, got, exp)
+		}
+	}
+}
+
+type rawCounter struct {
+	lines map[string]int
+}
+
+func (r *rawCounter) HandleRaw(line string) {
+	if r.lines == nil {
+		r.lines = map[string]int{}
+	}
+	r.lines[line]++
+}
+
+func TestProcessMulti(t *testing.T) {
+	h := &rawCounter{}
+	err := ProcessMulti([]io.Reader{
+		strings.NewReader(ubloxSample),
+		strings.NewReader(freeNmeaSample),
+	}, h, nil)
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := map[string]int{}
+	for _, s := range strings.Split(ubloxSample+freeNmeaSample, "\n") {
+		if s != "" {
+			exp[s]++
+		}
+	}
+	if !reflect.DeepEqual(h.lines, exp) {
+		t.Errorf("Expected %v distinct lines, got %v", len(exp), len(h.lines))
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken")
+}
+
+func TestProcessMultiErrors(t *testing.T) {
+	err := ProcessMulti([]io.Reader{strings.NewReader(ubloxSample), errReader{}}, nil, nil)
+	if err == nil || err.Error() != "broken" {
+		t.Errorf("Expected reader error, got %v", err)
+	}
+
+	err = ProcessMulti([]io.Reader{
+		strings.NewReader(freeNmeaSample),
+		strings.NewReader(ubloxSample + "$GPGGA,1*4B\n"),
+	}, &testUnion{}, func(s string, e error) error { return e })
+	if err == nil {
+		t.Errorf("Expected error parsing junk, got nil")
+	}
+}
+
+func TestProcessorDownsample(t *testing.T) {
+	b := NewSentenceBuilder(nil)
+	t0 := time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 10; i++ {
+		ts := t0.Add(time.Duration(i) * time.Second)
+		rmc, err := b.Build("RMC", RMC{Timestamp: ts, Status: 'A'}.Marshal())
+		if err != nil {
+			t.Fatalf("Error building RMC: %v", err)
+		}
+		gga, err := b.Build("GGA", GGA{Taken: ts, Quality: GPSFix}.Marshal())
+		if err != nil {
+			t.Fatalf("Error building GGA: %v", err)
+		}
+		lines = append(lines, rmc, gga, "$GPVTG,188.36,T,,M,0.820,N,1.519,K,A*3F\n")
+	}
+
+	var got []time.Time
+	h := &struct {
+		rmcRecorder
+		ggaHandler
+		vtgHandler
+		rawCounter
+	}{}
+	h.rmcRecorder.f = func(r RMC) { got = append(got, r.Timestamp) }
+
+	p := &Processor{}
+	p.Downsample(3 * time.Second)
+	if err := p.Process(strings.NewReader(strings.Join(lines, "")), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	// The last of each 3s window, the final one held until the end.
+	exp := []time.Time{t0.Add(2 * time.Second), t0.Add(5 * time.Second), t0.Add(8 * time.Second), t0.Add(9 * time.Second)}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected RMC at %v, got %v", exp, got)
+	}
+
+	st := p.Stats()
+	if st.ByType["RMC"] != 4 || st.ByType["GGA"] != 4 || st.ByType["VTG"] != 1 {
+		t.Errorf("Unexpected counts by type: %v", st.ByType)
+	}
+	if st.Dropped != 21 {
+		t.Errorf("Expected 21 dropped sentences, got %v", st.Dropped)
+	}
+	raw := 0
+	for _, n := range h.lines {
+		raw += n
+	}
+	if raw != 9 {
+		t.Errorf("Expected 9 raw sentences, got %v", raw)
+	}
+}
+
+func TestProcessorRejectQualities(t *testing.T) {
+	input := strings.Join([]string{
+		"$GPGGA,162254.00,3723.02837,N,12159.39853,W,8,03,2.36,525.6,M,-25.6,M,,*6C",
+		"$GPRMC,162255.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,S*67",
+		"$GPGGA,162255.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*64",
+		"$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74",
+	}, "\n")
+
+	var rejected []interface{}
+	var rmcs []RMC
+	var ggas []GGA
+	h := &struct {
+		rmcRecorder
+		ggaRecorder
+	}{rmcRecorder{func(r RMC) { rmcs = append(rmcs, r) }}, ggaRecorder{func(g GGA) { ggas = append(ggas, g) }}}
+
+	p := &Processor{
+		RejectQualities: []FixQuality{SimulationModeFix},
+		OnReject:        func(fix interface{}) { rejected = append(rejected, fix) },
+	}
+	if err := p.Process(strings.NewReader(input), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	if len(ggas) != 1 || ggas[0].Quality != GPSFix {
+		t.Errorf("Expected only the GPS fix GGA, got %v", ggas)
+	}
+	if len(rmcs) != 1 || rmcs[0].Timestamp.Second() != 54 {
+		t.Errorf("Expected only the autonomous RMC, got %v", rmcs)
+	}
+	if len(rejected) != 2 {
+		t.Fatalf("Expected two rejected fixes, got %v", rejected)
+	}
+	if g, ok := rejected[0].(GGA); !ok || g.Quality != SimulationModeFix {
+		t.Errorf("Expected a rejected simulated GGA, got %#v", rejected[0])
+	}
+	if _, ok := rejected[1].(RMC); !ok {
+		t.Errorf("Expected a rejected RMC, got %#v", rejected[1])
+	}
+
+	// By default everything gets through.
+	rmcs, ggas = nil, nil
+	if err := Process(strings.NewReader(input), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(rmcs) != 2 || len(ggas) != 2 {
+		t.Errorf("Expected all fixes by default, got %v and %v", rmcs, ggas)
+	}
+}
+
+type epochRecorder struct {
+	testUnion
+	events []string
+}
+
+func (e *epochRecorder) HandleEpoch(t time.Time) {
+	e.events = append(e.events, "epoch "+t.Format("15:04:05"))
+}
+
+func (e *epochRecorder) HandleRMC(RMC) { e.events = append(e.events, "RMC") }
+func (e *epochRecorder) HandleGGA(GGA) { e.events = append(e.events, "GGA") }
+
+func TestProcessorEpochs(t *testing.T) {
+	rmc1 := "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74\n"
+	gga1 := "$GPGGA,162254.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*65\n"
+	rmc2 := "$GPRMC,162255.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*75\n"
+	gga2 := "$GPGGA,162255.00,3723.02837,N,12159.39853,W,1,03,2.36,525.6,M,-25.6,M,,*64\n"
+
+	tests := []struct {
+		name  string
+		input string
+		exp   []string
+	}{
+		{"rmc and gga", rmc1 + gga1 + rmc2 + gga2,
+			[]string{"epoch 16:22:54", "RMC", "GGA", "epoch 16:22:55", "RMC", "GGA"}},
+		{"gga only", gga1 + gga2,
+			[]string{"epoch 16:22:54", "GGA", "epoch 16:22:55", "GGA"}},
+	}
+
+	for _, test := range tests {
+		h := &epochRecorder{}
+		if err := Process(strings.NewReader(test.input), h, nil); err != nil {
+			t.Fatalf("%v: error processing: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(h.events, test.exp) {
+			t.Errorf("%v: expected %v, got %v", test.name, test.exp, h.events)
+		}
+	}
+}
+
+func TestNewProcessor(t *testing.T) {
+	p := NewProcessor()
+	p.Strict = true
+	if err := p.Process(strings.NewReader(ubloxSample), &testUnion{}, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	p.Release()
+
+	p = NewProcessor()
+	defer p.Release()
+	if p.Strict || p.Stats().Sentences != 0 {
+		t.Errorf("Expected a fresh processor, got strict=%v, stats=%+v", p.Strict, p.Stats())
+	}
+	h := &testUnion{}
+	if err := p.Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if h.gsv.SentenceNum != 4 || h.zda.Timestamp.IsZero() {
+		t.Errorf("Expected the whole sample to be handled, got %#v", h)
+	}
+}
+
+func benchmarkStreams(b *testing.B, process func(io.Reader) error) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 100; j++ {
+			if err := process(strings.NewReader(ubloxSample)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkProcessStreams(b *testing.B) {
+	h := &testUnion{}
+	benchmarkStreams(b, func(r io.Reader) error {
+		return Process(r, h, nil)
+	})
+}
+
+func BenchmarkPooledProcessStreams(b *testing.B) {
+	h := &testUnion{}
+	benchmarkStreams(b, func(r io.Reader) error {
+		p := NewProcessor()
+		defer p.Release()
+		return p.Process(r, h, nil)
+	})
+}
+
+func TestProcessorBestEffort(t *testing.T) {
+	input := "$GPGGA,1*4B\n" + ubloxSample + "$GPRMC,1*56\n" + "$GPVTG,x,T,,M,0.820,N,1.519,K,A*5D\n"
+	errh := func(s string, e error) error { return e }
+
+	h := &testUnion{}
+	if err := Process(strings.NewReader(input), h, errh); err == nil {
+		t.Errorf("Expected processing to abort")
+	}
+	if !h.zda.Timestamp.IsZero() {
+		t.Errorf("Expected processing to stop at the first error")
+	}
+
+	p := &Processor{BestEffort: true}
+	h = &testUnion{}
+	err := p.Process(strings.NewReader(input), h, errh)
+	if err == nil {
+		t.Fatalf("Expected errors from best effort processing")
+	}
+	if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != 3 {
+		t.Errorf("Expected 3 errors, got %v: %v", got, err)
+	}
+	if h.zda.Timestamp.IsZero() || h.gsv.SentenceNum != 4 {
+		t.Errorf("Expected every good sentence to be handled, got %#v", h)
+	}
+	if st := p.Stats(); st.Parsed != 10 {
+		t.Errorf("Expected 10 sentences parsed, got %+v", st)
+	}
+}
+
+func TestProcessorMaxLength(t *testing.T) {
+	long := "$YXXDR,C,19.52,C,TEMP,P,1.02481,B,PRESS,H,52.7,P,RH,C,20.1,C,TTTTTTTTTTTTTTTTT*42"
+	limit := "$YXXDR,C,19.52,C,TEMP,P,1.02481,B,PRESS,H,52.7,P,RH,C,20.1,C,TTTTTTTTTTTTTTTT*16"
+	if len(long)+2 != 83 || len(limit)+2 != 82 {
+		t.Fatalf("Test lines are the wrong length")
+	}
+
+	var errs []error
+	p := &Processor{MaxLength: MaxSentenceLength}
+	h := &xdrHandler{}
+	err := p.Process(strings.NewReader(long+"\r\n"+limit+"\r\n"), h, func(s string, e error) error {
+		errs = append(errs, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if !reflect.DeepEqual(errs, []error{ErrTooLong}) {
+		t.Errorf("Expected only the long line to be rejected, got %v", errs)
+	}
+	if len(h.xdr.Measurements) != 4 || h.xdr.Measurements[3].Name != "TTTTTTTTTTTTTTTT" {
+		t.Errorf("Expected the line at the limit to be parsed, got %+v", h.xdr)
+	}
+	if st := p.Stats(); st.TooLong != 1 || st.Parsed != 1 {
+		t.Errorf("Expected one line too long and one parsed, got %+v", st)
+	}
+
+	if err := (&Processor{}).parseMessage(long, h); err != nil {
+		t.Errorf("Expected no limit by default, got %v", err)
+	}
+}
+
+func TestProcessorRejectEarly(t *testing.T) {
+	const input = "$GPRMC,000012.00,A,3723.02837,N,12159.39853,W,0.820,188.36,060180,,,A*7F\n" +
+		"$GPZDA,000012.00,06,01,1980,00,00*62\n" + ubloxSample
+
+	var errs []error
+	var fixes []RMC
+	p := &Processor{RejectEarly: true}
+	err := p.Process(strings.NewReader(input), &rmcRecorder{func(r RMC) { fixes = append(fixes, r) }},
+		func(s string, e error) error {
+			errs = append(errs, e)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if !reflect.DeepEqual(errs, []error{ErrEarlyTime, ErrEarlyTime}) {
+		t.Errorf("Expected both 1980 sentences to be rejected, got %v", errs)
+	}
+	if len(fixes) != 1 || fixes[0].Timestamp.Year() != 2006 {
+		t.Errorf("Expected only the 2006 fix, got %v", fixes)
+	}
+
+	// A later cutoff rejects the sample too.
+	errs = nil
+	p = &Processor{RejectEarly: true, EarliestTime: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p.Process(strings.NewReader(ubloxSample), nil, func(s string, e error) error {
+		errs = append(errs, e)
+		return nil
+	})
+	if len(errs) != 2 {
+		t.Errorf("Expected the sample's RMC and ZDA to be rejected, got %v", errs)
+	}
+
+	// A ZDA's time of day is UTC whatever its zone: 11:00:03Z here,
+	// before the cutoff.
+	errs = nil
+	p = &Processor{RejectEarly: true, EarliestTime: time.Date(2006, 3, 27, 14, 0, 0, 0, time.UTC)}
+	p.Process(strings.NewReader("$GPZDA,110003.00,27,03,2006,-5,00*7f\n"), nil, func(s string, e error) error {
+		errs = append(errs, e)
+		return nil
+	})
+	if !reflect.DeepEqual(errs, []error{ErrEarlyTime}) {
+		t.Errorf("Expected the zoned ZDA to be rejected, got %v", errs)
+	}
+}
+
+type windowRecorder struct {
+	rmcs, ggas []time.Time
+	gsvs       int
+}
+
+func (w *windowRecorder) HandleRMC(m RMC) { w.rmcs = append(w.rmcs, m.Timestamp) }
+func (w *windowRecorder) HandleGGA(m GGA) { w.ggas = append(w.ggas, m.Taken) }
+func (w *windowRecorder) HandleGSV(GSV)   { w.gsvs++ }
+
+func TestProcessorTimeWindow(t *testing.T) {
+	b := NewSentenceBuilder(nil)
+	t0 := time.Date(2006, 7, 11, 12, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i < 10; i++ {
+		ts := t0.Add(time.Duration(i) * 30 * time.Second)
+		rmc, err := b.Build("RMC", RMC{Timestamp: ts, Status: 'A'}.Marshal())
+		if err != nil {
+			t.Fatalf("Error building RMC: %v", err)
+		}
+		gga, err := b.Build("GGA", GGA{Taken: ts, Quality: GPSFix}.Marshal())
+		if err != nil {
+			t.Fatalf("Error building GGA: %v", err)
+		}
+		lines = append(lines, rmc, gga, "$GPGSV,3,1,11,03,03,111,00,04,15,270,00,06,01,010,00,13,06,292,00*74\n")
+	}
+	input := strings.Join(lines, "")
+
+	tests := []struct {
+		untimed bool
+		gsvs    int
+	}{
+		{false, 10},
+		{true, 0},
+	}
+	for _, test := range tests {
+		h := &windowRecorder{}
+		p := &Processor{DropUntimed: test.untimed}
+		p.TimeWindow(t0.Add(time.Minute), t0.Add(2*time.Minute))
+		if err := p.Process(strings.NewReader(input), h, nil); err != nil {
+			t.Fatalf("Error processing: %v", err)
+		}
+
+		exp := []time.Time{t0.Add(time.Minute), t0.Add(90 * time.Second)}
+		if !reflect.DeepEqual(h.rmcs, exp) {
+			t.Errorf("Expected RMC at %v, got %v", exp, h.rmcs)
+		}
+		if len(h.ggas) != 2 || timeOfDay(h.ggas[0]) != timeOfDay(exp[0]) || timeOfDay(h.ggas[1]) != timeOfDay(exp[1]) {
+			t.Errorf("Expected GGA at %v, got %v", exp, h.ggas)
+		}
+		if h.gsvs != test.gsvs {
+			t.Errorf("Expected %v GSV with DropUntimed=%v, got %v", test.gsvs, test.untimed, h.gsvs)
+		}
+		if st := p.Stats(); st.Dropped != int64(16+10-test.gsvs) {
+			t.Errorf("Expected %v dropped, got %v", 16+10-test.gsvs, st.Dropped)
+		}
+	}
+}
+
+func TestProcessorTimeWindowZoned(t *testing.T) {
+	// 11:00:03 UTC, in a zone 5 hours west.
+	const input = "$GPZDA,110003.00,27,03,2006,-5,00*7f\n"
+	tests := []struct {
+		start time.Time
+		n     int
+	}{
+		{time.Date(2006, 3, 27, 11, 0, 0, 0, time.UTC), 1},
+		{time.Date(2006, 3, 27, 16, 0, 0, 0, time.UTC), 0},
+	}
+	for _, test := range tests {
+		h := &zdaHandler{}
+		p := &Processor{}
+		p.TimeWindow(test.start, test.start.Add(5*time.Second))
+		if err := p.Process(strings.NewReader(input), h, nil); err != nil {
+			t.Fatalf("Error processing: %v", err)
+		}
+		if n := int(p.Stats().Parsed); n != test.n {
+			t.Errorf("Expected %v ZDA in the window from %v, got %v", test.n, test.start, n)
+		}
+	}
+}
+
+func TestProcessorStopWhen(t *testing.T) {
+	const input = "$GPGGA,120000.00,,,,,0,00,99.99,,,,,,*65\n" +
+		"$GPGSA,A,1,,,,,,,,,,,,,99.99,99.99,99.99*30\n" +
+		"$GPGGA,120001.00,,,,,0,00,99.99,,,,,,*64\n" +
+		"$GPGSA,A,1,,,,,,,,,,,,,99.99,99.99,99.99*30\n" +
+		"$GPGGA,120002.00,,,,,0,00,99.99,,,,,,*67\n" +
+		"$GPGSA,A,1,,,,,,,,,,,,,99.99,99.99,99.99*30\n" +
+		"$GPGGA,120003.00,3723.02837,N,12159.39853,W,1,05,1.5,525.6,M,-25.6,M,,*56\n" +
+		"$GPGSA,A,3,25,01,22,11,14,,,,,,,,2.5,1.5,2.0*30\n" +
+		"$GPGGA,120004.00,3723.02837,N,12159.39853,W,1,05,1.5,525.6,M,-25.6,M,,*51\n" +
+		"$GPGSA,A,3,25,01,22,11,14,,,,,,,,2.5,1.5,2.0*30\n"
+
+	f := &FixReady{MinSats: 4}
+	p := &Processor{StopWhen: f.Done}
+	if err := p.Process(strings.NewReader(input), f, nil); err != ErrStopped {
+		t.Fatalf("Expected ErrStopped, got %v", err)
+	}
+	if n := p.Stats().Sentences; n != 8 {
+		t.Errorf("Expected to stop after the first fix's GSA, 8 sentences in, got %v", n)
+	}
+	if d := p.Elapsed(); d != 3*time.Second {
+		t.Errorf("Expected a 3s time to first fix, got %v", d)
+	}
+}
+
+func TestProcessLog(t *testing.T) {
+	var stamped, hexed string
+	for i, line := range strings.Split(strings.TrimSpace(ubloxSample), "\n") {
+		stamped += fmt.Sprintf("%d.123: %s\r\n", 1520000000+i, line)
+	}
+	hexed = "24 47 50 5A 44 41 2C 31 36 32 32 35 34 2E 30 30 2C 31 31 2C 30 37 2C 32 30 30 36 2C 30 30 2C 30 30 2A 36 33 0D 0A\n"
+
+	tests := []struct {
+		name, input string
+	}{
+		{"plain", ubloxSample},
+		{"timestamped", stamped},
+		{"hex", strings.Replace(ubloxSample, "$GPZDA,162254.00,11,07,2006,00,00*63\n", hexed, 1)},
+	}
+	for _, test := range tests {
+		h := &testUnion{}
+		p := &Processor{}
+		err := p.ProcessLog(strings.NewReader(test.input), h, func(s string, e error) error { return e })
+		if err != nil {
+			t.Errorf("%v: error processing: %v", test.name, err)
+			continue
+		}
+		if st := p.Stats(); st.Parsed != 10 {
+			t.Errorf("%v: expected 10 sentences parsed, got %+v", test.name, st)
+		}
+		if h.zda.Timestamp.IsZero() {
+			t.Errorf("%v: expected ZDA to be handled", test.name)
+		}
+	}
+
+	err := ProcessLog(strings.NewReader("1520000000.123: garbage\n"), nil, func(s string, e error) error { return e })
+	if err != errBadChecksum {
+		t.Errorf("Expected bad checksum on garbage, got %v", err)
+	}
+}
+
+func TestProcessConcatenated(t *testing.T) {
+	const rmc = "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74"
+	const vtg = "$GPVTG,054.7,T,034.4,M,005.5,N,010.2,K*48"
+
+	h := &testUnion{}
+	var bad []string
+	err := Process(strings.NewReader(rmc+vtg+"$GPVTG,054.7,T*00\n"), h, func(s string, e error) error {
+		bad = append(bad, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if h.rmc.Timestamp.IsZero() {
+		t.Errorf("Expected RMC to be handled")
+	}
+	if h.vtg.True != 54.7 {
+		t.Errorf("Expected VTG to be handled, got %+v", h.vtg)
+	}
+	if len(bad) != 1 || bad[0] != "$GPVTG,054.7,T*00" {
+		t.Errorf("Expected only the final segment to fail, got %q", bad)
+	}
+}
+
+type gsvFlusher struct {
+	acc     GSVAccumulator
+	partial []GSVSatInfo
+}
+
+func (g *gsvFlusher) HandleGSV(m GSV) { g.acc.Add(m) }
+func (g *gsvFlusher) Flush()          { g.acc.Flush() }
+
+func TestProcessFlush(t *testing.T) {
+	const input = "$GPGSV,4,1,14,25,15,175,30,14,80,041,,19,38,259,14,01,52,223,18*76\n" +
+		"$GPGSV,4,2,14,18,16,079,,11,19,312,,14,80,041,,21,04,135,25*7D\n"
+
+	h := &gsvFlusher{}
+	h.acc.Partial = func(inView int, sats []GSVSatInfo) { h.partial = sats }
+	if err := Process(strings.NewReader(input), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(h.partial) != 8 {
+		t.Errorf("Expected 8 satellites flushed at EOF, got %v", h.partial)
+	}
+
+	// Wrapped handlers are flushed too.
+	h = &gsvFlusher{}
+	h.acc.Partial = func(inView int, sats []GSVSatInfo) { h.partial = sats }
+	if err := Process(strings.NewReader(input), &TeeHandler{W: io.Discard, Handler: h}, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(h.partial) != 8 {
+		t.Errorf("Expected 8 satellites flushed through a TeeHandler, got %v", h.partial)
+	}
+}
+
+// scanSTXETX splits input framed as STX sentence ETX, discarding
+// anything between frames.
+func scanSTXETX(data []byte, atEOF bool) (int, []byte, error) {
+	start := bytes.IndexByte(data, 0x02)
+	if start < 0 {
+		if atEOF {
+			return len(data), nil, nil
+		}
+		return 0, nil, nil
+	}
+	if end := bytes.IndexByte(data[start:], 0x03); end >= 0 {
+		return start + end + 1, data[start+1 : start+end], nil
+	}
+	if atEOF {
+		return len(data), nil, nil
+	}
+	return start, nil, nil
+}
+
+func TestProcessorSplitFunc(t *testing.T) {
+	var input bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSpace(ubloxSample), "\n") {
+		input.WriteString("\x02" + line + "\x03\xff")
+	}
+
+	h := &testUnion{}
+	p := &Processor{SplitFunc: scanSTXETX}
+	if err := p.Process(&input, h, func(s string, e error) error { return e }); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if st := p.Stats(); st.Parsed != 10 {
+		t.Errorf("Expected 10 sentences parsed, got %+v", st)
+	}
+	if h.zda.Timestamp.IsZero() {
+		t.Errorf("Expected ZDA to be handled")
+	}
+}
+
+func TestProcessTruncated(t *testing.T) {
+	const rmc = "$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74"
+
+	tests := []struct {
+		name, input string
+		exp         error
+	}{
+		{"terminated", rmc + "\n" + rmc + "\r\n", nil},
+		{"unterminated", rmc + "\n" + rmc, nil},
+		{"truncated", rmc + "\n" + rmc[:40], ErrTruncated},
+		{"blank", rmc + "\n\n", nil},
+	}
+	for _, test := range tests {
+		var handled []error
+		h := &testUnion{}
+		err := Process(strings.NewReader(test.input), h, func(s string, e error) error {
+			handled = append(handled, e)
+			return nil
+		})
+		if err != test.exp {
+			t.Errorf("%v: expected %v, got %v", test.name, test.exp, err)
+		}
+		if h.rmc.Timestamp.IsZero() {
+			t.Errorf("%v: expected the RMC to be handled", test.name)
+		}
+		if test.exp == nil && len(handled) > 0 {
+			t.Errorf("%v: unexpected errors %v", test.name, handled)
+		}
+		if test.exp != nil && (len(handled) != 1 || !errors.Is(handled[0], ErrTruncated) || !errors.Is(handled[0], errBadChecksum)) {
+			t.Errorf("%v: expected a truncated checksum error, got %v", test.name, handled)
+		}
+	}
+}
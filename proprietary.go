@@ -0,0 +1,129 @@
+package nmea
+
+import "fmt"
+
+// Proprietary sentences begin with P and a three letter manufacturer
+// code.  They're dispatched by their whole address, and some
+// manufacturers further multiplex message types within it.
+
+var pubxParsers = map[string]func(*Processor, []string, interface{}) error{
+	"00": pubx00Parser,
+}
+
+func pubxParser(p *Processor, parts []string, handler interface{}) error {
+	if len(parts) < 2 {
+		return errShortMsg
+	}
+	f, ok := pubxParsers[parts[1]]
+	if !ok {
+		return ErrUnhandled
+	}
+	return f(p, parts, handler)
+}
+
+/*
+	$PUBX,00,081350.00,4717.113210,N,00833.915187,E,546.589,G3,2.1,2.0,0.007,77.52,0.007,,0.92,1.19,0.77,9,0,0*5F
+
+Where:
+
+	1:     00            Message ID
+	2:     081350.00     UTC time
+	3,4:   4717.113210,N Latitude
+	5,6:   00833.915187,E Longitude
+	7:     546.589       Altitude above user datum ellipsoid (m)
+	8:     G3            Navigation status
+	9:     2.1           Horizontal accuracy estimate (m)
+	10:    2.0           Vertical accuracy estimate (m)
+	11:    0.007         Speed over ground (km/h)
+	12:    77.52         Course over ground (degrees)
+	13:    0.007         Vertical velocity (m/s, positive downwards)
+	14:    (empty)       Age of differential corrections (s)
+	15:    0.92          HDOP
+	16:    1.19          VDOP
+	17:    0.77          TDOP
+	18:    9             Number of satellites used
+	19:    0             Reserved
+	20:    0             Dead reckoning used
+*/
+func pubx00Parser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(PUBX00Handler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 19 {
+		return fmt.Errorf("unexpected PUBX,00 packet: %#v (len=%v)", parts, len(parts))
+	}
+
+	t, err := parseTimeOfDay(parts[2])
+	if err != nil {
+		return err
+	}
+
+	ns, ok := pubxNavStatCodes[parts[8]]
+	if !ok {
+		return fmt.Errorf("unexpected PUBX,00 nav status: %q", parts[8])
+	}
+
+	cp := p.newParser()
+	pubx := PUBX00{
+		Taken:              t,
+		Latitude:           cp.parseDMS(parts[3], parts[4]),
+		Longitude:          cp.parseDMS(parts[5], parts[6]),
+		Altitude:           cp.parseFloat(parts[7]),
+		NavStat:            ns,
+		HorizontalAccuracy: cp.parseFloat(parts[9]),
+		VerticalAccuracy:   cp.parseFloat(parts[10]),
+		SpeedKMH:           cp.parseFloat(parts[11]),
+		Course:             cp.parseFloat(parts[12]),
+		VerticalVelocity:   cp.parseFloat(parts[13]),
+		DiffAge:            cp.parseFloat(parts[14]),
+		HDOP:               cp.parseFloat(parts[15]),
+		VDOP:               cp.parseFloat(parts[16]),
+		TDOP:               cp.parseFloat(parts[17]),
+		NumSats:            cp.parseInt(parts[18]),
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandlePUBX00(pubx)
+
+	return nil
+}
+
+/*
+	$PGRME,15.0,M,45.0,M,25.0,M*1C
+
+Where:
+
+	1,2: 15.0,M  Estimated horizontal position error (meters)
+	3,4: 45.0,M  Estimated vertical position error (meters)
+	5,6: 25.0,M  Estimated overall spherical position error (meters)
+*/
+func pgrmeParser(p *Processor, parts []string, handler interface{}) error {
+	h, ok := handler.(PGRMEHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(parts) < 7 || parts[2] != "M" || parts[4] != "M" || parts[6] != "M" {
+		return fmt.Errorf("unexpected PGRME packet: %#v", parts)
+	}
+
+	cp := p.newParser()
+	pgrme := PGRME{
+		HorizontalError: cp.parseFloat(parts[1]),
+		VerticalError:   cp.parseFloat(parts[3]),
+		SphericalError:  cp.parseFloat(parts[5]),
+	}
+
+	if cp.err != nil {
+		return cp.err
+	}
+
+	h.HandlePGRME(pgrme)
+
+	return nil
+}
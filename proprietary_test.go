@@ -0,0 +1,106 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type pubx00Handler struct {
+	pubx PUBX00
+}
+
+func (p *pubx00Handler) HandlePUBX00(pubx PUBX00) {
+	p.pubx = pubx
+}
+
+func TestPUBX00Handling(t *testing.T) {
+	h := &pubx00Handler{}
+	err := (&Processor{}).parseMessage("$PUBX,00,081350.00,4717.113210,N,00833.915187,E,546.589,G3,2.1,2.0,0.007,77.52,0.007,,0.92,1.19,0.77,9,0,0*5F", h)
+	if err != nil {
+		t.Fatalf("Error parsing PUBX,00: %v", err)
+	}
+	exp := PUBX00{
+		Taken:              time.Date(0, 1, 1, 8, 13, 50, 0, time.UTC),
+		Latitude:           47.2852201666666,
+		Longitude:          8.565253116666667,
+		Altitude:           546.589,
+		NavStat:            PUBX3D,
+		HorizontalAccuracy: 2.1,
+		VerticalAccuracy:   2.0,
+		SpeedKMH:           0.007,
+		Course:             77.52,
+		VerticalVelocity:   0.007,
+		HDOP:               0.92,
+		VDOP:               1.19,
+		TDOP:               0.77,
+		NumSats:            9,
+	}
+	if !similar(t, h.pubx, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.pubx, exp)
+	}
+	if h.pubx.NavStat.String() != "3D fix" {
+		t.Errorf("Expected 3D fix, got %v", h.pubx.NavStat)
+	}
+}
+
+func TestPUBXErrors(t *testing.T) {
+	tests := []string{
+		"$PUBX,00,081350.00,4717.113210,N,00833.915187,E,546.589,XX,2.1,2.0,0.007,77.52,0.007,,0.92,1.19,0.77,9,0,0",
+		"$PUBX,00,081350.00,4717.113210,N",
+		"$PUBX,99,081350.00,4717.113210,N,00833.915187,E,546.589,G3,2.1,2.0,0.007,77.52,0.007,,0.92,1.19,0.77,9,0,0",
+		"$PUBX",
+	}
+	for _, test := range tests {
+		if err := pubxParser(&Processor{}, strings.Split(test, ","), &pubx00Handler{}); err == nil {
+			t.Errorf("Expected error parsing %q", test)
+		}
+	}
+}
+
+func TestPUBXNavStatString(t *testing.T) {
+	tests := map[PUBXNavStat]string{
+		PUBXNoFix:        "no fix",
+		PUBXTimeOnly:     "time only",
+		PUBXNavStat(-1):  "[Invalid PUBX NavStat: -1]",
+		PUBXNavStat(100): "[Invalid PUBX NavStat: 100]",
+	}
+	for n, exp := range tests {
+		if got := n.String(); got != exp {
+			t.Errorf("Got %q, expected %q", got, exp)
+		}
+	}
+}
+
+type pgrmeHandler struct {
+	pgrme PGRME
+}
+
+func (p *pgrmeHandler) HandlePGRME(pgrme PGRME) {
+	p.pgrme = pgrme
+}
+
+func TestPGRMEHandling(t *testing.T) {
+	h := &pgrmeHandler{}
+	if err := (&Processor{}).parseMessage("$PGRME,15.0,M,45.0,M,25.0,M*1C", h); err != nil {
+		t.Fatalf("Error parsing PGRME: %v", err)
+	}
+	exp := PGRME{HorizontalError: 15, VerticalError: 45, SphericalError: 25}
+	if !similar(t, h.pgrme, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.pgrme, exp)
+	}
+}
+
+func TestPGRMEErrors(t *testing.T) {
+	tests := []string{
+		"$PGRME,15.0,M,45.0,M,25.0,F",
+		"$PGRME,15.0,f,45.0,M,25.0,M",
+		"$PGRME,15.0,M,45.0,M",
+		"$PGRME,x,M,45.0,M,25.0,M",
+	}
+	for _, test := range tests {
+		if err := pgrmeParser(&Processor{}, strings.Split(test, ","), &pgrmeHandler{}); err == nil {
+			t.Errorf("Expected error parsing %q", test)
+		}
+	}
+}
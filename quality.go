@@ -0,0 +1,89 @@
+package nmea
+
+import (
+	"math"
+	"time"
+)
+
+// SignalQuality scores the signals of the satellites in view from 0
+// (none usable) to 100, for a quick indication of reception.
+//
+// Each satellite whose SNR is at least Floor contributes its SNR
+// divided by 45 dB-Hz, a strong signal, up to a maximum of 1.  The
+// score is 100 times the sum of contributions divided by Target,
+// capped at 100 and rounded to the nearest integer.  So Target
+// satellites with strong signals score 100, while weak satellites
+// count for less and those below Floor not at all.
+//
+// Satellites are combined over the complete sets of GSV sentences of
+// a fix cycle, counting each satellite once with its best SNR, so
+// every constellation tracked contributes.  Satellites of different
+// constellations sharing a PRN are told apart by the talker of their
+// GSV, which SignalQuality notes as a RawHandler.
+type SignalQuality struct {
+	// Floor is the lowest SNR in dB-Hz counted.  The default is 30.
+	Floor int
+	// Target is the number of strong satellites scoring 100.  The
+	// default is 8.
+	Target int
+	// Denylist lists satellites to leave out.
+	Denylist []SatID
+
+	talker    string
+	acc       GSVAccumulator
+	cur, prev map[SatID]int // best SNR by satellite
+}
+
+// HandleRaw satisfies RawHandler.
+func (q *SignalQuality) HandleRaw(line string) {
+	q.talker = talkerID(line)
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (q *SignalQuality) HandleEpoch(t time.Time) {
+	if len(q.cur) > 0 {
+		q.prev = q.cur
+	}
+	q.cur = nil
+	q.acc = GSVAccumulator{}
+}
+
+// HandleGSV satisfies GSVHandler.
+func (q *SignalQuality) HandleGSV(g GSV) {
+	if !q.acc.Add(g) {
+		return
+	}
+	if q.cur == nil {
+		q.cur = map[SatID]int{}
+	}
+	for _, si := range allowedSats(q.acc.SatInfo, q.talker, q.Denylist) {
+		id := si.ID(q.talker)
+		if prev, ok := q.cur[id]; !ok || si.SNR > prev {
+			q.cur[id] = si.SNR
+		}
+	}
+}
+
+// Score returns the score of the current fix cycle, or of the
+// previous one if no complete GSV set has been seen in this one yet.
+func (q *SignalQuality) Score() int {
+	snrs := q.cur
+	if len(snrs) == 0 {
+		snrs = q.prev
+	}
+	floor, target := q.Floor, q.Target
+	if floor == 0 {
+		floor = 30
+	}
+	if target == 0 {
+		target = 8
+	}
+
+	sum := 0.0
+	for _, snr := range snrs {
+		if snr >= floor {
+			sum += math.Min(float64(snr)/45, 1)
+		}
+	}
+	return int(math.Round(100 * math.Min(sum/float64(target), 1)))
+}
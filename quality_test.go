@@ -0,0 +1,33 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignalQuality(t *testing.T) {
+	tests := []struct {
+		q   SignalQuality
+		exp int
+	}{
+		// Only PRN 25 (30 dB-Hz) reaches the default floor.
+		{SignalQuality{}, 8},
+		// PRNs 25, 21, 01, 15 (its better listing) and 22.
+		{SignalQuality{Floor: 15}, 30},
+		{SignalQuality{Floor: 15, Target: 2}, 100},
+		{SignalQuality{Floor: 31}, 0},
+	}
+	for _, test := range tests {
+		q := test.q
+		if err := Process(strings.NewReader(ubloxSample+ubloxSample), &q, nil); err != nil {
+			t.Fatalf("Error processing: %v", err)
+		}
+		if got := q.Score(); got != test.exp {
+			t.Errorf("With floor %v and target %v, expected %v, got %v", test.q.Floor, test.q.Target, test.exp, got)
+		}
+	}
+
+	if got := (&SignalQuality{}).Score(); got != 0 {
+		t.Errorf("Expected 0 with no satellites, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package nmea
+
+// FixReady waits for a receiver to have a usable fix, calling Ready
+// the first time it does.
+//
+// A fix is usable once the latest GSA reports a 3D fix with a PDOP
+// no greater than MaxPDOP, the latest GGA reports a valid fix using
+// at least MinSats satellites and, for receivers sending RMC, the
+// latest RMC is valid.
+type FixReady struct {
+	// MinSats is the fewest satellites a usable fix may use.
+	MinSats int
+	// MaxPDOP is the largest PDOP of a usable fix, or 0 for no
+	// limit.
+	MaxPDOP float64
+	// Ready is called with the GGA completing the first usable
+	// fix.
+	Ready func(GGA)
+
+	gga      *GGA
+	gsa      *GSA
+	rmcSeen  bool
+	rmcValid bool
+	fired    bool
+}
+
+// HandleGGA satisfies GGAHandler.
+func (f *FixReady) HandleGGA(g GGA) {
+	f.gga = &g
+	f.check()
+}
+
+// HandleGSA satisfies GSAHandler.
+func (f *FixReady) HandleGSA(g GSA) {
+	f.gsa = &g
+	f.check()
+}
+
+// HandleRMC satisfies RMCHandler.
+func (f *FixReady) HandleRMC(r RMC) {
+	f.rmcSeen = true
+	f.rmcValid = r.Valid()
+	f.check()
+}
+
+// Done reports whether Ready has been called, i.e. a usable fix was
+// seen.
+func (f *FixReady) Done() bool {
+	return f.fired
+}
+
+// Reset forgets everything seen, so Ready is called again at the
+// next usable fix.
+func (f *FixReady) Reset() {
+	*f = FixReady{MinSats: f.MinSats, MaxPDOP: f.MaxPDOP, Ready: f.Ready}
+}
+
+func (f *FixReady) usable() bool {
+	switch {
+	case f.gga == nil || f.gsa == nil:
+		return false
+	case !f.gga.Valid() || f.gga.NumSats < f.MinSats:
+		return false
+	case !f.gsa.Fix.Has3D() || (f.MaxPDOP > 0 && !f.gsa.Usable(f.MaxPDOP)):
+		return false
+	case f.rmcSeen && !f.rmcValid:
+		return false
+	}
+	return true
+}
+
+func (f *FixReady) check() {
+	if f.fired || !f.usable() {
+		return
+	}
+	f.fired = true
+	if f.Ready != nil {
+		f.Ready(*f.gga)
+	}
+}
@@ -0,0 +1,48 @@
+package nmea
+
+import "testing"
+
+func TestFixReady(t *testing.T) {
+	var fired []GGA
+	f := &FixReady{MinSats: 6, MaxPDOP: 3, Ready: func(g GGA) { fired = append(fired, g) }}
+
+	steps := []struct {
+		msg   interface{}
+		fired int
+	}{
+		{GGA{Quality: GPSFix, NumSats: 4}, 0},
+		{GSA{Fix: Fix2D, PDOP: 2}, 0},
+		{GSA{Fix: Fix3D, PDOP: 5}, 0},
+		{GSA{Fix: Fix3D, PDOP: 2}, 0}, // too few satellites
+		{RMC{Status: 'V'}, 0},
+		{GGA{Quality: GPSFix, NumSats: 7}, 0}, // RMC still void
+		{RMC{Status: 'A'}, 1},
+		{GGA{Quality: GPSFix, NumSats: 8}, 1},
+	}
+	for i, step := range steps {
+		switch m := step.msg.(type) {
+		case GGA:
+			f.HandleGGA(m)
+		case GSA:
+			f.HandleGSA(m)
+		case RMC:
+			f.HandleRMC(m)
+		}
+		if len(fired) != step.fired {
+			t.Fatalf("After step %v (%+v), expected %v calls, got %v", i, step.msg, step.fired, len(fired))
+		}
+	}
+	if fired[0].NumSats != 7 {
+		t.Errorf("Expected the fix with 7 satellites, got %+v", fired[0])
+	}
+
+	f.Reset()
+	f.HandleGGA(GGA{Quality: GPSFix, NumSats: 8})
+	if len(fired) != 1 {
+		t.Errorf("Expected no call without a fresh GSA after Reset")
+	}
+	f.HandleGSA(GSA{Fix: Fix3D, PDOP: 1.5})
+	if len(fired) != 2 {
+		t.Errorf("Expected another call after Reset, got %v", len(fired))
+	}
+}
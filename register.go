@@ -0,0 +1,44 @@
+package nmea
+
+// A ParserFunc parses a sentence split into its comma separated
+// fields, the first being the address (e.g. "$GPRMC") and the last
+// excluding the checksum.  It should pass the result to handler if
+// handler implements the interface for the sentence type, and
+// return an error if the sentence is malformed.
+//
+// parts is a buffer the Processor reuses for every sentence, so it's
+// only valid during the call: a parser keeping any of it, e.g. in a
+// slice field of its message, must copy it first.
+type ParserFunc func(parts []string, handler interface{}) error
+
+// addressParsers holds parsers registered for a specific talker, by
+// full address (e.g. "GPRMC").
+var addressParsers = map[string]func(*Processor, []string, interface{}) error{}
+
+func wrapParser(f ParserFunc) func(*Processor, []string, interface{}) error {
+	return func(p *Processor, parts []string, handler interface{}) error {
+		return f(parts, handler)
+	}
+}
+
+// RegisterParser registers a parser for a sentence type (e.g. "RMC",
+// or "PGRME" for proprietary sentences), replacing any built in
+// parser for that type.
+//
+// Parsers must be registered before processing starts, typically
+// from an init function.
+func RegisterParser(typ string, f ParserFunc) {
+	parsers[typ] = wrapParser(f)
+}
+
+// RegisterTalkerParser registers a parser for a sentence type sent
+// by a specific talker (e.g. "GN"), for vendors whose variant of a
+// standard sentence has a different layout.  Sentences from other
+// talkers are parsed as before.  A talker scoped parser is preferred
+// over one registered for the type alone.
+//
+// Parsers must be registered before processing starts, typically
+// from an init function.
+func RegisterTalkerParser(talker, typ string, f ParserFunc) {
+	addressParsers[talker+typ] = wrapParser(f)
+}
@@ -0,0 +1,77 @@
+package nmea
+
+import (
+	"strconv"
+	"testing"
+)
+
+type vendorRMC struct {
+	Active bool
+	Depth  float64
+}
+
+type vendorRMCHandler struct {
+	rmcHandler
+	vendor vendorRMC
+}
+
+func (v *vendorRMCHandler) HandleVendorRMC(m vendorRMC) {
+	v.vendor = m
+}
+
+func parseVendorRMC(parts []string, handler interface{}) error {
+	h, ok := handler.(interface{ HandleVendorRMC(vendorRMC) })
+	if !ok {
+		return nil
+	}
+	if len(parts) < 3 {
+		return errShortMsg
+	}
+	d, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return err
+	}
+	h.HandleVendorRMC(vendorRMC{parts[1] == "A", d})
+	return nil
+}
+
+func TestRegisterTalkerParser(t *testing.T) {
+	RegisterTalkerParser("II", "RMC", parseVendorRMC)
+	defer delete(addressParsers, "IIRMC")
+
+	h := &vendorRMCHandler{}
+	if err := (&Processor{}).parseMessage("$IIRMC,A,12.5*05", h); err != nil {
+		t.Fatalf("Error parsing vendor RMC: %v", err)
+	}
+	if exp := (vendorRMC{true, 12.5}); h.vendor != exp {
+		t.Errorf("Expected %v, got %v", exp, h.vendor)
+	}
+	if !h.rmc.Timestamp.IsZero() {
+		t.Errorf("Expected the built in parser to be skipped, got %v", h.rmc)
+	}
+
+	if err := (&Processor{}).parseMessage("$IIRMC,A,x*65", h); err == nil {
+		t.Errorf("Expected error from vendor parser")
+	}
+
+	h = &vendorRMCHandler{}
+	if err := (&Processor{}).parseMessage("$GPRMC,162254.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A*74", h); err != nil {
+		t.Fatalf("Error parsing standard RMC: %v", err)
+	}
+	if h.rmc.Timestamp.IsZero() || h.vendor.Active {
+		t.Errorf("Expected the built in parser for $GPRMC, got %v / %v", h.rmc, h.vendor)
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	RegisterParser("XYZ", parseVendorRMC)
+	defer delete(parsers, "XYZ")
+
+	h := &vendorRMCHandler{}
+	if err := (&Processor{}).parseMessage("$GPXYZ,1*51", h); err == nil {
+		t.Errorf("Expected error from registered parser")
+	}
+	if err := (&Processor{}).parseMessage("$GPXYZ,1*51", &rmcHandler{}); err != nil {
+		t.Errorf("Unexpected error for uninterested handler: %v", err)
+	}
+}
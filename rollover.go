@@ -0,0 +1,72 @@
+package nmea
+
+import "time"
+
+// GPSWeekRollover is the period of the 10 bit GPS week number.
+// Receivers mishandling its rollover (as happened in 1999 and 2019)
+// report dates a multiple of it in the past.
+const GPSWeekRollover = 1024 * 7 * 24 * time.Hour
+
+// RolloverDetector reports RMC and ZDA dates implausibly far from a
+// known reference time, such as those of receivers affected by the
+// GPS week number rollover.
+type RolloverDetector struct {
+	// Reference is roughly when the data was recorded.
+	Reference time.Time
+	// MaxSkew is how far a date may be from Reference before it's
+	// reported.  The default is a year.
+	MaxSkew time.Duration
+	// Rollover is called with each implausible time, and the time
+	// corrected by adding whole multiples of GPSWeekRollover.
+	// corrected is the same as t if no correction brings it close
+	// to Reference.
+	Rollover func(t, corrected time.Time)
+}
+
+func (r *RolloverDetector) maxSkew() time.Duration {
+	if r.MaxSkew == 0 {
+		return 365 * 24 * time.Hour
+	}
+	return r.MaxSkew
+}
+
+func (r *RolloverDetector) near(t time.Time) bool {
+	d := t.Sub(r.Reference)
+	return d <= r.maxSkew() && -d <= r.maxSkew()
+}
+
+// Correct returns t corrected for up to four GPS week number
+// rollovers, and whether it needed correcting.  A time that can't be
+// brought close to Reference is returned unchanged.
+func (r *RolloverDetector) Correct(t time.Time) (time.Time, bool) {
+	if r.near(t) {
+		return t, false
+	}
+	for i := 1; i <= 4; i++ {
+		if c := t.Add(time.Duration(i) * GPSWeekRollover); r.near(c) {
+			return c, true
+		}
+	}
+	return t, true
+}
+
+// HandleRMC satisfies RMCHandler.
+func (r *RolloverDetector) HandleRMC(m RMC) {
+	if m.Valid() {
+		r.check(m.Timestamp)
+	}
+}
+
+// HandleZDA satisfies ZDAHandler.
+func (r *RolloverDetector) HandleZDA(z ZDA) {
+	r.check(z.Timestamp)
+}
+
+func (r *RolloverDetector) check(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if c, bad := r.Correct(t); bad && r.Rollover != nil {
+		r.Rollover(t, c)
+	}
+}
@@ -0,0 +1,50 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRolloverDetector(t *testing.T) {
+	const input = "$GPRMC,120000,A,3723.02837,N,12159.39853,W,0.0,0.0,010619,,,A*6F\n" +
+		"$GPRMC,120000,A,3723.02837,N,12159.39853,W,0.0,0.0,161099,,,A*66\n" +
+		"$GPZDA,120000.00,01,06,2005,00,00*65\n"
+
+	var got, corrected []time.Time
+	r := &RolloverDetector{
+		Reference: time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC),
+		Rollover: func(t, c time.Time) {
+			got = append(got, t)
+			corrected = append(corrected, c)
+		},
+	}
+	if err := Process(strings.NewReader(input), r, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	exp := []time.Time{
+		time.Date(1999, 10, 16, 12, 0, 0, 0, time.UTC),
+		time.Date(2005, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	expCorrected := []time.Time{
+		time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC),
+		exp[1],
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected rollovers at %v, got %v", exp, got)
+	}
+	if !reflect.DeepEqual(corrected, expCorrected) {
+		t.Errorf("Expected corrections to %v, got %v", expCorrected, corrected)
+	}
+}
+
+func TestRolloverCorrectTwice(t *testing.T) {
+	r := &RolloverDetector{Reference: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	in := time.Date(1984, 8, 22, 0, 0, 0, 0, time.UTC)
+	got, bad := r.Correct(in)
+	if exp := in.Add(2 * GPSWeekRollover); !bad || !got.Equal(exp) {
+		t.Errorf("Expected %v corrected to %v, got %v, %v", in, exp, got, bad)
+	}
+}
@@ -0,0 +1,108 @@
+package nmea
+
+// A RoutePoint is a single waypoint along a Route.
+type RoutePoint struct {
+	Name                string
+	Latitude, Longitude float64
+	// Missing is true if no WPL for the waypoint was seen, in
+	// which case its position is unknown.
+	Missing bool
+}
+
+// A Route is an ordered list of waypoints.
+type Route struct {
+	ID     string
+	Points []RoutePoint
+}
+
+// RouteBuilder assembles routes from RTE messages and the WPL
+// messages locating their waypoints.
+//
+// Once every sentence of an RTE sequence has arrived and a WPL has
+// been seen for each of its waypoints, Route is called with the
+// assembled route.  A route still waiting on waypoints is delivered
+// with the unlocated waypoints marked Missing when the next RTE
+// sequence begins, or by Flush.
+type RouteBuilder struct {
+	// Route is called with each assembled route.
+	Route func(Route)
+
+	waypoints map[string]WPL
+
+	id      string
+	names   []string
+	parts   int
+	prev    int
+	pending bool
+}
+
+// HandleWPL satisfies WPLHandler.
+func (b *RouteBuilder) HandleWPL(w WPL) {
+	if b.waypoints == nil {
+		b.waypoints = map[string]WPL{}
+	}
+	b.waypoints[w.Name] = w
+	if b.pending && b.located() {
+		b.emit()
+	}
+}
+
+// HandleRTE satisfies RTEHandler.
+func (b *RouteBuilder) HandleRTE(r RTE) {
+	if r.SentenceNum == 1 {
+		b.Flush()
+		b.id = r.RouteID
+		b.names = nil
+		b.parts = r.TotalSentences
+		b.prev = 0
+		b.pending = false
+	}
+	if r.RouteID != b.id || r.TotalSentences != b.parts || r.SentenceNum != b.prev+1 {
+		// Out of sequence; wait for the start of the next one.
+		b.prev = -1
+		return
+	}
+	b.prev = r.SentenceNum
+	b.names = append(b.names, r.Waypoints...)
+
+	if b.prev == b.parts {
+		b.pending = true
+		if b.located() {
+			b.emit()
+		}
+	}
+}
+
+func (b *RouteBuilder) located() bool {
+	for _, n := range b.names {
+		if _, ok := b.waypoints[n]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *RouteBuilder) emit() {
+	rt := Route{ID: b.id}
+	for _, n := range b.names {
+		w, ok := b.waypoints[n]
+		rt.Points = append(rt.Points, RoutePoint{
+			Name:      n,
+			Latitude:  w.Latitude,
+			Longitude: w.Longitude,
+			Missing:   !ok,
+		})
+	}
+	b.pending = false
+	if b.Route != nil {
+		b.Route(rt)
+	}
+}
+
+// Flush delivers a complete route still waiting on waypoint
+// locations.
+func (b *RouteBuilder) Flush() {
+	if b.pending {
+		b.emit()
+	}
+}
@@ -0,0 +1,144 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type rteHandler struct {
+	rte RTE
+}
+
+func (r *rteHandler) HandleRTE(rte RTE) {
+	r.rte = rte
+}
+
+type wplHandler struct {
+	wpl WPL
+}
+
+func (w *wplHandler) HandleWPL(wpl WPL) {
+	w.wpl = wpl
+}
+
+func TestRTEHandling(t *testing.T) {
+	h := &rteHandler{}
+	err := (&Processor{}).parseMessage("$GPRTE,2,2,c,0,PCRESY,GRYRIE,GCORIO,GWERR,GWESTG,7FED*34", h)
+	if err != nil {
+		t.Fatalf("Error parsing RTE: %v", err)
+	}
+	exp := RTE{
+		TotalSentences: 2,
+		SentenceNum:    2,
+		Complete:       true,
+		RouteID:        "0",
+		Waypoints:      []string{"PCRESY", "GRYRIE", "GCORIO", "GWERR", "GWESTG", "7FED"},
+	}
+	if !similar(t, h.rte, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.rte, exp)
+	}
+
+	if err := rteParser(&Processor{}, []string{"$GPRTE", "1", "1", "x", "0", "A"}, h); err == nil {
+		t.Errorf("Expected error parsing RTE with an invalid mode")
+	}
+}
+
+func TestWPLHandling(t *testing.T) {
+	h := &wplHandler{}
+	if err := (&Processor{}).parseMessage("$GPWPL,4917.16,N,12310.64,W,003*65", h); err != nil {
+		t.Fatalf("Error parsing WPL: %v", err)
+	}
+	exp := WPL{Latitude: 49.286, Longitude: -123.177333333, Name: "003"}
+	if !similar(t, h.wpl, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", h.wpl, exp)
+	}
+}
+
+func TestRouteBuilder(t *testing.T) {
+	input := strings.Join([]string{
+		"$GPWPL,3723.000,N,12200.000,W,W1*3B",
+		"$GPWPL,3724.000,N,12201.000,W,W2*3E",
+		"$GPRTE,1,1,c,0,W1,W2,W3,W4*03",
+		"$GPWPL,3725.000,N,12202.000,W,W3*3D",
+		"$GPWPL,3726.000,N,12203.000,W,W4*38",
+	}, "\n")
+
+	var got []Route
+	b := &RouteBuilder{Route: func(r Route) { got = append(got, r) }}
+	if err := Process(strings.NewReader(input), b, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	b.Flush()
+
+	exp := []Route{{ID: "0", Points: []RoutePoint{
+		{"W1", 37.383333333333333, -122, false},
+		{"W2", 37.4, -122.016666666666667, false},
+		{"W3", 37.416666666666667, -122.033333333333333, false},
+		{"W4", 37.433333333333333, -122.05, false},
+	}}}
+	if len(got) != 1 || len(got[0].Points) != 4 {
+		t.Fatalf("Expected %v, got %v", exp, got)
+	}
+	for i, p := range got[0].Points {
+		if !similar(t, p, exp[0].Points[i]) {
+			t.Errorf("Point %v: expected %v, got %v", i, exp[0].Points[i], p)
+		}
+	}
+}
+
+func TestRouteBuilderMissing(t *testing.T) {
+	var got []Route
+	b := &RouteBuilder{Route: func(r Route) { got = append(got, r) }}
+
+	b.HandleWPL(WPL{Name: "A", Latitude: 1, Longitude: 2})
+	b.HandleRTE(RTE{TotalSentences: 2, SentenceNum: 1, Complete: true, RouteID: "r", Waypoints: []string{"A", "B"}})
+	b.HandleRTE(RTE{TotalSentences: 2, SentenceNum: 2, Complete: true, RouteID: "r", Waypoints: []string{"C"}})
+	if len(got) != 0 {
+		t.Fatalf("Expected no route before flushing, got %v", got)
+	}
+	b.Flush()
+	b.Flush()
+
+	exp := []Route{{ID: "r", Points: []RoutePoint{
+		{"A", 1, 2, false},
+		{"B", 0, 0, true},
+		{"C", 0, 0, true},
+	}}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %v, got %v", exp, got)
+	}
+}
+
+func TestRouteBuilderBackToBack(t *testing.T) {
+	var got []Route
+	b := &RouteBuilder{Route: func(r Route) { got = append(got, r) }}
+
+	b.HandleWPL(WPL{Name: "A", Latitude: 1, Longitude: 2})
+	b.HandleRTE(RTE{TotalSentences: 1, SentenceNum: 1, Complete: true, RouteID: "r", Waypoints: []string{"A", "B"}})
+	b.HandleRTE(RTE{TotalSentences: 1, SentenceNum: 1, Complete: true, RouteID: "s", Waypoints: []string{"A"}})
+	b.Flush()
+
+	exp := []Route{
+		{ID: "r", Points: []RoutePoint{{"A", 1, 2, false}, {"B", 0, 0, true}}},
+		{ID: "s", Points: []RoutePoint{{"A", 1, 2, false}}},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %v, got %v", exp, got)
+	}
+}
+
+func TestRouteBuilderSequence(t *testing.T) {
+	var got []Route
+	b := &RouteBuilder{Route: func(r Route) { got = append(got, r) }}
+
+	// Missing the first sentence.
+	b.HandleRTE(RTE{TotalSentences: 2, SentenceNum: 2, RouteID: "r", Waypoints: []string{"C"}})
+	// Skipping the second.
+	b.HandleRTE(RTE{TotalSentences: 3, SentenceNum: 1, RouteID: "r", Waypoints: []string{"A"}})
+	b.HandleRTE(RTE{TotalSentences: 3, SentenceNum: 3, RouteID: "r", Waypoints: []string{"C"}})
+	b.Flush()
+	if len(got) != 0 {
+		t.Errorf("Expected no routes from broken sequences, got %v", got)
+	}
+}
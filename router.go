@@ -0,0 +1,75 @@
+package nmea
+
+import "time"
+
+// TalkerRouter returns a handler passing each sentence to the handler
+// routes maps its talker ID (e.g. "GP") to, or to def for other
+// talkers and proprietary sentences.  It lets one stream combining
+// several receivers be handled per receiver, e.g.
+//
+//	nmea.Process(r, nmea.TalkerRouter(map[string]interface{}{
+//		"GP": antenna1,
+//		"GN": antenna2,
+//	}, nil), nil)
+//
+// def may be nil.  Sentences are routed by their raw line, so the
+// returned handler must receive them, either from Process or through
+// wrappers such as TeeHandler and MultiHandler.  Fix cycles span
+// talkers, so epochs and flushes go to every handler.  As with
+// MultiHandler, errors are reported for sentences of any type their
+// route didn't want.
+func TalkerRouter(routes map[string]interface{}, def interface{}) interface{} {
+	r := &talkerRouter{
+		routes: make(map[string]*multiHandler, len(routes)),
+		def:    &multiHandler{},
+	}
+	var all []interface{}
+	for talker, h := range routes {
+		r.routes[talker] = MultiHandler(h).(*multiHandler)
+		all = append(all, h)
+	}
+	if def != nil {
+		r.def = MultiHandler(def).(*multiHandler)
+		all = append(all, def)
+	}
+	r.all = MultiHandler(all...).(*multiHandler)
+	r.multiHandler = r.def
+	return r
+}
+
+// talkerRouter passes parsed sentences to the route of the last raw
+// line seen.
+type talkerRouter struct {
+	*multiHandler // the current route
+
+	routes   map[string]*multiHandler
+	def, all *multiHandler
+}
+
+// talkerID returns the talker ID of a sentence, or "" if it's
+// proprietary or malformed.
+func talkerID(line string) string {
+	if len(line) < 3 || (line[0] != '$' && line[0] != '!') || line[1] == 'P' {
+		return ""
+	}
+	return line[1:3]
+}
+
+// HandleRaw satisfies RawHandler, choosing the route of the sentence.
+func (r *talkerRouter) HandleRaw(s string) {
+	r.multiHandler = r.def
+	if h, ok := r.routes[talkerID(s)]; ok {
+		r.multiHandler = h
+	}
+	r.multiHandler.HandleRaw(s)
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (r *talkerRouter) HandleEpoch(t time.Time) {
+	r.all.HandleEpoch(t)
+}
+
+// Flush satisfies Flusher.
+func (r *talkerRouter) Flush() {
+	r.all.Flush()
+}
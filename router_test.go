@@ -0,0 +1,41 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type talkerRecorder struct {
+	gsvs   []GSV
+	epochs int
+}
+
+func (r *talkerRecorder) HandleGSV(m GSV)         { r.gsvs = append(r.gsvs, m) }
+func (r *talkerRecorder) HandleEpoch(t time.Time) { r.epochs++ }
+
+func TestTalkerRouter(t *testing.T) {
+	input := ubloxSample +
+		"$GNGSA,A,3,25,01,22,,,,,,,,,,2.56,2.36,1.00*1D\n" +
+		"$GLGSV,1,1,03,65,40,083,46,66,17,308,41,67,07,344,*55\n"
+
+	gp, gl := &talkerRecorder{}, &talkerRecorder{}
+	others := &rawCounter{}
+	h := TalkerRouter(map[string]interface{}{"GP": gp, "GL": gl}, others)
+	if err := Process(strings.NewReader(input), h, func(string, error) error { return nil }); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	if len(gp.gsvs) != 4 || gp.gsvs[0].InView != 14 {
+		t.Errorf("Expected the 4 GPS GSVs routed to GP, got %+v", gp.gsvs)
+	}
+	if len(gl.gsvs) != 1 || gl.gsvs[0].InView != 3 {
+		t.Errorf("Expected the GLONASS GSV routed to GL, got %+v", gl.gsvs)
+	}
+	if len(others.lines) != 1 || others.lines["$GNGSA,A,3,25,01,22,,,,,,,,,,2.56,2.36,1.00*1D"] != 1 {
+		t.Errorf("Expected only the GN sentence routed to the default, got %v", others.lines)
+	}
+	if gp.epochs != 1 || gl.epochs != 1 {
+		t.Errorf("Expected each route to see the epoch, got %v and %v", gp.epochs, gl.epochs)
+	}
+}
@@ -0,0 +1,136 @@
+package nmea
+
+import "sort"
+
+// SatStat summarizes the observations of a single satellite.
+type SatStat struct {
+	Constellation Constellation
+	PRN           int
+	// Seen is the number of complete GSV sets listing the satellite.
+	Seen int
+	// Tracked is the number of those sets reporting a nonzero SNR.
+	// The SNR statistics are computed over tracked sets only.
+	Tracked        int
+	MinSNR, MaxSNR int
+	MeanSNR        float64
+}
+
+type satCounter struct {
+	SatStat
+	total int
+}
+
+// SatStats accumulates per-satellite visibility statistics over a
+// stream of GSV messages.
+//
+// Messages are stitched together with a GSVAccumulator so that each
+// complete set counts once per satellite.  Satellites are told apart
+// by constellation and PRN, the constellation being found from the
+// talker of each GSV, which SatStats notes as a RawHandler.
+type SatStats struct {
+	// Denylist lists satellites to leave out, e.g. to assess
+	// reception as if a misbehaving satellite were absent.
+	Denylist []SatID
+
+	talker string
+	acc    GSVAccumulator
+	sets   int
+	stats  map[SatID]*satCounter
+}
+
+// HandleRaw satisfies RawHandler.
+func (s *SatStats) HandleRaw(line string) {
+	s.talker = talkerID(line)
+}
+
+// HandleGSV satisfies GSVHandler.
+func (s *SatStats) HandleGSV(g GSV) {
+	if !s.acc.Add(g) {
+		return
+	}
+	if s.stats == nil {
+		s.stats = map[SatID]*satCounter{}
+	}
+	s.sets++
+
+	// A satellite may be listed more than once in a set; keep the
+	// best SNR.
+	snrs := map[SatID]int{}
+	for _, si := range allowedSats(s.acc.SatInfo, s.talker, s.Denylist) {
+		id := si.ID(s.talker)
+		if prev, ok := snrs[id]; !ok || si.SNR > prev {
+			snrs[id] = si.SNR
+		}
+	}
+
+	for id, snr := range snrs {
+		c, ok := s.stats[id]
+		if !ok {
+			c = &satCounter{SatStat: SatStat{Constellation: id.Constellation, PRN: id.PRN}}
+			s.stats[id] = c
+		}
+		c.Seen++
+		if snr == 0 {
+			continue
+		}
+		if c.Tracked == 0 || snr < c.MinSNR {
+			c.MinSNR = snr
+		}
+		if snr > c.MaxSNR {
+			c.MaxSNR = snr
+		}
+		c.Tracked++
+		c.total += snr
+	}
+}
+
+// denied reports whether id is in denylist.
+func denied(denylist []SatID, id SatID) bool {
+	for _, d := range denylist {
+		if d == id {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedSats returns the satellites of sats, described by a GSV from
+// talker, not in denylist.  sats itself is returned if there's
+// nothing to leave out.
+func allowedSats(sats []GSVSatInfo, talker string, denylist []SatID) []GSVSatInfo {
+	if len(denylist) == 0 {
+		return sats
+	}
+	var rv []GSVSatInfo
+	for _, si := range sats {
+		if !denied(denylist, si.ID(talker)) {
+			rv = append(rv, si)
+		}
+	}
+	return rv
+}
+
+// Sets returns the number of complete GSV sets observed.
+func (s *SatStats) Sets() int {
+	return s.sets
+}
+
+// Report returns the statistics for every satellite seen, ordered by
+// constellation and then PRN.
+func (s *SatStats) Report() []SatStat {
+	rv := make([]SatStat, 0, len(s.stats))
+	for _, c := range s.stats {
+		st := c.SatStat
+		if c.Tracked > 0 {
+			st.MeanSNR = float64(c.total) / float64(c.Tracked)
+		}
+		rv = append(rv, st)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].Constellation != rv[j].Constellation {
+			return rv[i].Constellation < rv[j].Constellation
+		}
+		return rv[i].PRN < rv[j].PRN
+	})
+	return rv
+}
@@ -0,0 +1,143 @@
+package nmea
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSatStats(t *testing.T) {
+	sets := [][]GSV{
+		{
+			{TotalSentences: 2, SentenceNum: 1, InView: 5, SatInfo: []GSVSatInfo{
+				{1, 40, 83, 40}, {2, 17, 308, 30}, {3, 7, 344, 0}, {4, 22, 228, 45},
+			}},
+			{TotalSentences: 2, SentenceNum: 2, InView: 5, SatInfo: []GSVSatInfo{
+				{5, 10, 100, 20},
+			}},
+		},
+		{
+			{TotalSentences: 1, SentenceNum: 1, InView: 3, SatInfo: []GSVSatInfo{
+				{1, 41, 83, 44}, {2, 17, 308, 0}, {4, 23, 228, 41},
+			}},
+		},
+		{
+			{TotalSentences: 2, SentenceNum: 1, InView: 4, SatInfo: []GSVSatInfo{
+				{1, 42, 84, 39}, {2, 18, 307, 36}, {4, 24, 229, 40}, {2, 18, 307, 0},
+			}},
+			{TotalSentences: 2, SentenceNum: 2, InView: 4},
+		},
+		{
+			// Incomplete set; must not be counted.
+			{TotalSentences: 2, SentenceNum: 1, InView: 2, SatInfo: []GSVSatInfo{
+				{1, 42, 84, 10}, {6, 18, 307, 10},
+			}},
+		},
+	}
+
+	s := &SatStats{}
+	for _, set := range sets {
+		for _, g := range set {
+			s.HandleGSV(g)
+		}
+	}
+	if s.Sets() != 3 {
+		t.Errorf("Expected 3 sets, got %v", s.Sets())
+	}
+
+	exp := []SatStat{
+		{Constellation: GPSConstellation, PRN: 1, Seen: 3, Tracked: 3, MinSNR: 39, MaxSNR: 44, MeanSNR: 41},
+		{Constellation: GPSConstellation, PRN: 2, Seen: 3, Tracked: 2, MinSNR: 30, MaxSNR: 36, MeanSNR: 33},
+		{Constellation: GPSConstellation, PRN: 3, Seen: 1},
+		{Constellation: GPSConstellation, PRN: 4, Seen: 3, Tracked: 3, MinSNR: 40, MaxSNR: 45, MeanSNR: 42},
+		{Constellation: GPSConstellation, PRN: 5, Seen: 1, Tracked: 1, MinSNR: 20, MaxSNR: 20, MeanSNR: 20},
+	}
+	if got := s.Report(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected\n%+v\ngot\n%+v", exp, got)
+	}
+}
+
+func TestDenylist(t *testing.T) {
+	deny := []SatID{{GPSConstellation, 25}}
+	stats := &SatStats{Denylist: deny}
+	summary := &SatSummary{Denylist: deny}
+	quality := &SignalQuality{Denylist: deny}
+	var plotted []SkySat
+	sky := &SkyPlot{Denylist: deny, Plot: func(sats []SkySat) { plotted = sats }}
+	h := MultiHandler(stats, summary, quality, sky)
+	if err := Process(strings.NewReader(ubloxSample), h, func(string, error) error { return nil }); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+
+	report := stats.Report()
+	if len(report) != 11 {
+		t.Errorf("Expected 11 satellites reported, got %v", report)
+	}
+	for _, st := range report {
+		if st.PRN == 25 {
+			t.Errorf("Denied PRN 25 was reported: %+v", st)
+		}
+	}
+	if len(plotted) != 11 {
+		t.Errorf("Expected 11 satellites plotted, got %v", plotted)
+	}
+	for _, sat := range plotted {
+		if sat.PRN == 25 {
+			t.Errorf("Denied PRN 25 was plotted: %+v", sat)
+		}
+	}
+	if summary.Used() != 2 || summary.InView() != 13 {
+		t.Errorf("Expected 2 of 13 satellites used, got %v of %v", summary.Used(), summary.InView())
+	}
+	// PRN 25 was the only satellite above the default floor.
+	if got := quality.Score(); got != 0 {
+		t.Errorf("Expected a score of 0, got %v", got)
+	}
+}
+
+func TestSatIDs(t *testing.T) {
+	// GPS and Galileo satellites numbered 1, as under NMEA 4.10.
+	input := "$GPGSV,1,1,02,01,40,083,46,02,17,308,41*7D\n" +
+		"$GAGSV,1,1,02,01,50,120,44,03,20,200,38*65\n" +
+		"$GNGSA,A,3,01,02,,,,,,,,,,,2.56,2.36,1.00,1*05\n" +
+		"$GNGSA,A,3,01,03,,,,,,,,,,,2.56,2.36,1.00,3*06\n"
+
+	tests := []struct {
+		deny    []SatID
+		sats    int
+		quality int
+	}{
+		{nil, 4, 47},
+		{[]SatID{{GalileoConstellation, 1}}, 3, 34},
+	}
+	for _, test := range tests {
+		stats := &SatStats{Denylist: test.deny}
+		summary := &SatSummary{Denylist: test.deny}
+		quality := &SignalQuality{Denylist: test.deny}
+		var plotted []SkySat
+		sky := &SkyPlot{Denylist: test.deny, Plot: func(sats []SkySat) { plotted = sats }}
+		h := MultiHandler(stats, summary, quality, sky)
+		if err := Process(strings.NewReader(input), h, nil); err != nil {
+			t.Fatalf("Error processing: %v", err)
+		}
+
+		if got := len(stats.Report()); got != test.sats {
+			t.Errorf("With %v denied, expected %v satellites reported, got %v", test.deny, test.sats, stats.Report())
+		}
+		if len(plotted) != test.sats {
+			t.Errorf("With %v denied, expected %v satellites plotted, got %v", test.deny, test.sats, plotted)
+		}
+		for _, sat := range plotted {
+			if !sat.Used {
+				t.Errorf("With %v denied, expected %+v to be used", test.deny, sat)
+			}
+		}
+		if summary.Used() != test.sats || summary.InView() != test.sats {
+			t.Errorf("With %v denied, expected %v of %v satellites used, got %v of %v",
+				test.deny, test.sats, test.sats, summary.Used(), summary.InView())
+		}
+		if got := quality.Score(); got != test.quality {
+			t.Errorf("With %v denied, expected a score of %v, got %v", test.deny, test.quality, got)
+		}
+	}
+}
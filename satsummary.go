@@ -0,0 +1,97 @@
+package nmea
+
+import "time"
+
+// SatSummary counts the satellites used in the fix against those in
+// view, for example for a signal quality display.
+//
+// Receivers tracking several constellations report each in its own
+// GSA and GSV sentences, so counts are combined over a fix cycle:
+// satellites used are those listed by any GSA (or the GGA's count if
+// the receiver sends no GSA), and satellites in view are totalled
+// over each complete set of GSV sentences.  Counts reset at the start
+// of each cycle, so they're complete once the cycle's last sentence
+// has been handled.  Satellites are told apart by constellation and
+// PRN, found from the talker of each sentence, which SatSummary notes
+// as a RawHandler, and the system ID of GSAs.
+type SatSummary struct {
+	// Denylist lists satellites to leave out of both counts.  The
+	// GGA's count of satellites used can't be filtered, so it's
+	// used as is.
+	Denylist []SatID
+
+	talker  string
+	used    map[SatID]bool
+	ggaUsed int
+	inView  int
+	acc     GSVAccumulator
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (s *SatSummary) HandleEpoch(t time.Time) {
+	s.used = nil
+	s.ggaUsed = 0
+	s.inView = 0
+	s.acc = GSVAccumulator{}
+}
+
+// HandleRaw satisfies RawHandler.
+func (s *SatSummary) HandleRaw(line string) {
+	s.talker = talkerID(line)
+}
+
+// HandleGGA satisfies GGAHandler.
+func (s *SatSummary) HandleGGA(g GGA) {
+	s.ggaUsed = g.NumSats
+}
+
+// HandleGSA satisfies GSAHandler.
+func (s *SatSummary) HandleGSA(g GSA) {
+	if s.used == nil {
+		s.used = map[SatID]bool{}
+	}
+	for _, id := range g.SatIDs(s.talker) {
+		if !denied(s.Denylist, id) {
+			s.used[id] = true
+		}
+	}
+}
+
+// HandleGSV satisfies GSVHandler.
+func (s *SatSummary) HandleGSV(g GSV) {
+	if !s.acc.Add(g) {
+		return
+	}
+	s.inView += s.acc.InView
+	if len(s.Denylist) > 0 {
+		seen := map[SatID]bool{}
+		for _, si := range s.acc.SatInfo {
+			if id := si.ID(s.talker); denied(s.Denylist, id) && !seen[id] {
+				seen[id] = true
+				s.inView--
+			}
+		}
+	}
+}
+
+// Used returns the number of satellites used in the fix.
+func (s *SatSummary) Used() int {
+	if s.used != nil {
+		return len(s.used)
+	}
+	return s.ggaUsed
+}
+
+// InView returns the number of satellites in view.
+func (s *SatSummary) InView() int {
+	return s.inView
+}
+
+// UtilizationRatio returns the fraction of satellites in view that
+// are used in the fix, or 0 if none are in view.
+func (s *SatSummary) UtilizationRatio() float64 {
+	if s.inView == 0 {
+		return 0
+	}
+	return float64(s.Used()) / float64(s.inView)
+}
@@ -0,0 +1,41 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSatSummary(t *testing.T) {
+	s := &SatSummary{}
+	if err := Process(strings.NewReader(ubloxSample), s, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if s.Used() != 3 || s.InView() != 14 {
+		t.Errorf("Expected 3 of 14 satellites used, got %v of %v", s.Used(), s.InView())
+	}
+	if r := s.UtilizationRatio(); !near(r, 3.0/14) {
+		t.Errorf("Expected utilization of 3/14, got %v", r)
+	}
+}
+
+func TestSatSummaryMultiConstellation(t *testing.T) {
+	input := ubloxSample +
+		"$GNGSA,A,3,25,01,22,,,,,,,,,,2.56,2.36,1.00*1D\n" +
+		"$GNGSA,A,3,65,66,,,,,,,,,,,2.56,2.36,1.00*18\n" +
+		"$GLGSV,1,1,03,65,40,083,46,66,17,308,41,67,07,344,*55\n"
+	s := &SatSummary{}
+	if err := Process(strings.NewReader(input), s, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if s.Used() != 5 || s.InView() != 17 {
+		t.Errorf("Expected 5 of 17 satellites used, got %v of %v", s.Used(), s.InView())
+	}
+
+	// A new cycle starts over.
+	s.HandleEpoch(time.Time{})
+	s.HandleGGA(GGA{NumSats: 7})
+	if s.Used() != 7 || s.InView() != 0 || s.UtilizationRatio() != 0 {
+		t.Errorf("Expected 7 used from GGA and none in view, got %v of %v", s.Used(), s.InView())
+	}
+}
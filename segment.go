@@ -0,0 +1,120 @@
+package nmea
+
+import "time"
+
+// A Segment is a period during which a receiver was either moving or
+// stopped.
+type Segment struct {
+	Moving     bool
+	Start, End time.Time
+	// Distance is the great-circle distance covered between the
+	// fixes in the segment.
+	Distance Meters
+}
+
+// SegmentDetector splits a stream of RMC messages into moving and
+// stopped segments.
+//
+// A transition happens when the speed stays on the other side of
+// Threshold for at least Dwell, so brief excursions (e.g. GPS noise
+// while parked) don't split a segment.  A segment's boundary is the
+// timestamp of the first fix that crossed the threshold.  Void fixes
+// are ignored.
+type SegmentDetector struct {
+	// Threshold is the speed in knots at or above which the
+	// receiver is considered moving.
+	Threshold float64
+	// Dwell is how long the speed must remain across the threshold
+	// before a transition is reported.
+	Dwell time.Duration
+	// Segment is called with each completed segment.
+	Segment func(Segment)
+
+	started bool
+	cur     Segment
+	prev    RMC
+
+	pending     bool
+	pendingAt   time.Time
+	pendingDist Meters
+
+	moved Meters
+}
+
+// HandleRMC satisfies RMCHandler.
+func (s *SegmentDetector) HandleRMC(r RMC) {
+	if !r.Valid() {
+		return
+	}
+	moving := r.Speed >= s.Threshold
+	if !s.started {
+		s.started = true
+		s.cur = Segment{Moving: moving, Start: r.Timestamp, End: r.Timestamp}
+		s.prev = r
+		return
+	}
+
+	d := Distance(s.prev.Latitude, s.prev.Longitude, r.Latitude, r.Longitude)
+	s.prev = r
+	s.cur.Distance += d
+	s.cur.End = r.Timestamp
+	if s.cur.Moving {
+		s.moved += d
+	}
+
+	switch {
+	case moving == s.cur.Moving:
+		s.pending = false
+	case !s.pending:
+		s.pending = true
+		s.pendingAt = r.Timestamp
+		s.pendingDist = 0
+	default:
+		s.pendingDist += d
+		if r.Timestamp.Sub(s.pendingAt) >= s.Dwell {
+			s.transition()
+		}
+	}
+}
+
+func (s *SegmentDetector) transition() {
+	done := s.cur
+	done.End = s.pendingAt
+	done.Distance -= s.pendingDist
+	s.emit(done)
+
+	if done.Moving {
+		s.moved -= s.pendingDist
+	} else {
+		s.moved += s.pendingDist
+	}
+
+	s.cur = Segment{
+		Moving:   !done.Moving,
+		Start:    s.pendingAt,
+		End:      s.cur.End,
+		Distance: s.pendingDist,
+	}
+	s.pending = false
+}
+
+func (s *SegmentDetector) emit(seg Segment) {
+	if s.Segment != nil {
+		s.Segment(seg)
+	}
+}
+
+// Flush reports the segment in progress, if any, and resets the
+// detector.
+func (s *SegmentDetector) Flush() {
+	if s.started {
+		s.emit(s.cur)
+	}
+	s.started = false
+	s.pending = false
+}
+
+// MovingDistance returns the total distance covered while moving.
+func (s *SegmentDetector) MovingDistance() Meters {
+	return s.moved
+}
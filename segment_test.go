@@ -0,0 +1,53 @@
+package nmea
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSegmentDetector(t *testing.T) {
+	t0 := time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC)
+	speeds := []float64{0, 0.3, 0, 10, 10, 10, 10, 0.5, 10, 10, 10, 0, 0, 0, 0, 0}
+
+	var got []Segment
+	s := &SegmentDetector{
+		Threshold: 2,
+		Dwell:     3 * time.Second,
+		Segment:   func(seg Segment) { got = append(got, seg) },
+	}
+
+	lon := 0.0
+	for i, sp := range speeds {
+		s.HandleRMC(RMC{
+			Timestamp: t0.Add(time.Duration(i) * time.Second),
+			Status:    'A',
+			Longitude: lon,
+			Speed:     sp,
+		})
+		if sp >= 2 {
+			lon += 0.001
+		}
+	}
+	s.Flush()
+
+	step := Distance(0, 0, 0, 0.001)
+	exp := []Segment{
+		{false, t0, t0.Add(3 * time.Second), 0},
+		{true, t0.Add(3 * time.Second), t0.Add(11 * time.Second), 7 * step},
+		{false, t0.Add(11 * time.Second), t0.Add(15 * time.Second), 0},
+	}
+
+	if len(got) != len(exp) {
+		t.Fatalf("Expected %v segments, got %+v", len(exp), got)
+	}
+	for i := range exp {
+		if got[i].Moving != exp[i].Moving || !got[i].Start.Equal(exp[i].Start) ||
+			!got[i].End.Equal(exp[i].End) || !near(float64(got[i].Distance), float64(exp[i].Distance)) {
+			t.Errorf("Segment %v: expected %+v, got %+v", i, exp[i], got[i])
+		}
+	}
+
+	if !near(float64(s.MovingDistance()), float64(7*step)) {
+		t.Errorf("Expected moving distance %v, got %v", 7*step, s.MovingDistance())
+	}
+}
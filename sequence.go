@@ -0,0 +1,89 @@
+package nmea
+
+import (
+	"sort"
+	"time"
+)
+
+// SequenceChecker watches for fix cycles missing sentence types the
+// receiver normally emits every cycle.
+//
+// A healthy receiver emits the same set of sentences each cycle, so
+// a missing type usually means bytes were dropped on the wire even
+// though the sentences that did arrive are intact.
+//
+// SequenceChecker learns the expected set from the first Learn
+// complete cycles, taking the types present in every one of them.
+// Cycle boundaries come from HandleEpoch and sentence types from
+// HandleRaw, so SequenceChecker needs no typed handlers of its own.
+type SequenceChecker struct {
+	// Learn is the number of cycles used to learn the expected
+	// sentence types.  Zero means 3.
+	Learn int
+	// Missing is called with the start time of each cycle lacking
+	// expected types, and those types, sorted.
+	Missing func(epoch time.Time, types []string)
+
+	started  bool
+	epoch    time.Time
+	seen     map[string]bool
+	learned  int
+	expected map[string]bool
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (s *SequenceChecker) HandleEpoch(t time.Time) {
+	if s.started {
+		s.Flush()
+	}
+	s.started = true
+	s.epoch = t
+	s.seen = map[string]bool{}
+}
+
+// HandleRaw satisfies RawHandler.
+func (s *SequenceChecker) HandleRaw(line string) {
+	// Sentences before the first cycle boundary may belong to a
+	// partial cycle, so they're ignored.
+	if s.started {
+		s.seen[sentenceType(line)] = true
+	}
+}
+
+// Flush checks the current cycle as if it were complete.  Call it at
+// the end of the stream to check the last cycle.
+func (s *SequenceChecker) Flush() {
+	if !s.started {
+		return
+	}
+	s.started = false
+
+	learn := s.Learn
+	if learn == 0 {
+		learn = 3
+	}
+	if s.learned < learn {
+		if s.expected == nil {
+			s.expected = s.seen
+		} else {
+			for typ := range s.expected {
+				if !s.seen[typ] {
+					delete(s.expected, typ)
+				}
+			}
+		}
+		s.learned++
+		return
+	}
+
+	var missing []string
+	for typ := range s.expected {
+		if !s.seen[typ] {
+			missing = append(missing, typ)
+		}
+	}
+	if len(missing) > 0 && s.Missing != nil {
+		sort.Strings(missing)
+		s.Missing(s.epoch, missing)
+	}
+}
@@ -0,0 +1,51 @@
+package nmea
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSequenceChecker(t *testing.T) {
+	gsa := "$GPGSA,A,2,17,03,07,,,,,,,,,,2.54,2.36,1.00*04\n"
+	epoch := func(s int) string {
+		rmc := fmt.Sprintf("$GPRMC,1622%02d.00,A,3723.02837,N,12159.39853,W,0.820,188.36,110706,,,A", s)
+		return fmt.Sprintf("%s*%02X\n", rmc, Checksum(rmc))
+	}
+
+	var input string
+	for s := 50; s < 55; s++ {
+		input += epoch(s)
+		if s != 53 {
+			input += gsa
+		}
+	}
+
+	type report struct {
+		epoch time.Time
+		types []string
+	}
+	var got []report
+	h := &struct {
+		SequenceChecker
+		testUnion
+	}{SequenceChecker: SequenceChecker{
+		Learn: 2,
+		Missing: func(epoch time.Time, types []string) {
+			got = append(got, report{epoch, types})
+		},
+	}}
+	if err := Process(strings.NewReader(input), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	h.Flush()
+
+	exp := []report{
+		{time.Date(2006, 7, 11, 16, 22, 53, 0, time.UTC), []string{"GSA"}},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %v, got %v", exp, got)
+	}
+}
@@ -0,0 +1,67 @@
+package nmea
+
+import "math"
+
+// segmentDistance returns the distance from p to the nearest point of
+// the great-circle segment from a to b.
+func segmentDistance(p, a, b TrackPoint) Meters {
+	dap := Distance(a.Latitude, a.Longitude, p.Latitude, p.Longitude)
+	dab := Distance(a.Latitude, a.Longitude, b.Latitude, b.Longitude)
+	if dab == 0 {
+		return dap
+	}
+	δ13 := float64(dap) / earthRadius
+	θ := d2r(Bearing(a.Latitude, a.Longitude, p.Latitude, p.Longitude) -
+		Bearing(a.Latitude, a.Longitude, b.Latitude, b.Longitude))
+
+	// Cross-track distance from the great circle, and the distance
+	// along it from a to the point nearest p.
+	xt := math.Asin(math.Sin(δ13) * math.Sin(θ))
+	at := Meters(math.Acos(math.Min(1, math.Cos(δ13)/math.Cos(xt))) * earthRadius)
+	switch {
+	case math.Cos(θ) < 0:
+		return dap
+	case at > dab:
+		return Distance(b.Latitude, b.Longitude, p.Latitude, p.Longitude)
+	}
+	return Meters(math.Abs(xt) * earthRadius)
+}
+
+// Simplify reduces a track to fewer points using the Ramer-Douglas-
+// Peucker algorithm.  No point dropped is more than tolerance from
+// the simplified track, and the first and last points are always
+// kept.  The points retained are returned in their original order.
+func Simplify(points []TrackPoint, tolerance Meters) []TrackPoint {
+	if len(points) < 3 {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		worst, idx := Meters(0), -1
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentDistance(points[i], points[s.first], points[s.last]); d > worst {
+				worst, idx = d, i
+			}
+		}
+		if idx >= 0 && worst > tolerance {
+			keep[idx] = true
+			stack = append(stack, span{s.first, idx}, span{idx, s.last})
+		}
+	}
+
+	var rv []TrackPoint
+	for i, k := range keep {
+		if k {
+			rv = append(rv, points[i])
+		}
+	}
+	return rv
+}
@@ -0,0 +1,72 @@
+package nmea
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentDistance(t *testing.T) {
+	a := TrackPoint{Latitude: 0, Longitude: 0}
+	b := TrackPoint{Latitude: 0, Longitude: 1}
+	oneDeg := Distance(0, 0, 1, 0)
+
+	tests := []struct {
+		p   TrackPoint
+		exp Meters
+	}{
+		{TrackPoint{Latitude: 1, Longitude: 0.5}, oneDeg},
+		{TrackPoint{Latitude: -1, Longitude: 0.5}, oneDeg},
+		{TrackPoint{Latitude: 0, Longitude: 0.5}, 0},
+		{TrackPoint{Latitude: 0, Longitude: -1}, Distance(0, 0, 0, -1)},
+		{TrackPoint{Latitude: 0, Longitude: 2}, Distance(0, 1, 0, 2)},
+	}
+	for _, test := range tests {
+		// Allow for the curvature of the earth.
+		if got := segmentDistance(test.p, a, b); math.Abs(float64(got-test.exp)) > 20 {
+			t.Errorf("Distance from %+v: expected %v, got %v", test.p, test.exp, got)
+		}
+	}
+	if got := segmentDistance(b, a, a); math.Abs(float64(got-Distance(0, 0, 0, 1))) > 0.001 {
+		t.Errorf("Expected distance to a degenerate segment's point, got %v", got)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	// Heading east along the equator for about 1.1km, then north,
+	// zigzagging about 11m (1e-4°) either side of the way.
+	var points []TrackPoint
+	for i := 0; i <= 20; i++ {
+		zig := 0.0001 * float64(i%2*2-1)
+		if i <= 10 {
+			points = append(points, TrackPoint{Latitude: zig, Longitude: 0.001 * float64(i)})
+		} else {
+			points = append(points, TrackPoint{Latitude: 0.001 * float64(i-10), Longitude: 0.01 + zig})
+		}
+	}
+
+	tests := []struct {
+		tolerance Meters
+		exp       []int
+	}{
+		{100, []int{0, 10, 20}},
+		{5, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}},
+		{2000, []int{0, 20}},
+	}
+	for _, test := range tests {
+		got := Simplify(points, test.tolerance)
+		if len(got) != len(test.exp) {
+			t.Errorf("With tolerance %v, expected %v points, got %v", test.tolerance, len(test.exp), len(got))
+			continue
+		}
+		for i, idx := range test.exp {
+			if got[i] != points[idx] {
+				t.Errorf("With tolerance %v, point %v should be original point %v, got %+v",
+					test.tolerance, i, idx, got[i])
+			}
+		}
+	}
+
+	if got := Simplify(points[:2], 1000); len(got) != 2 {
+		t.Errorf("Expected short tracks to be unchanged, got %v", got)
+	}
+}
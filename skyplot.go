@@ -0,0 +1,86 @@
+package nmea
+
+import "time"
+
+// SkySat is a satellite's position in the sky, for drawing a sky plot.
+type SkySat struct {
+	GSVSatInfo
+	// Used is true if a GSA listed the satellite as used in the fix.
+	Used bool
+}
+
+// SkyPlot combines the GSV and GSA sentences of each fix cycle into
+// the satellites in view and whether each is used, e.g. for a polar
+// plot of an antenna's view of the sky.
+//
+// Satellites are listed once each, in the order their GSV sentences
+// list them, which for multi-constellation receivers is one
+// constellation after another.  Only complete sets of GSV sentences
+// are included.  Satellites are matched by constellation and PRN,
+// found from the talker of each GSA and GSV, which SkyPlot notes as a
+// RawHandler, and the system ID of GSAs.
+type SkyPlot struct {
+	// Plot is called with the satellites in view at the end of each
+	// fix cycle that reported any.  The slice is not reused.
+	Plot func(sats []SkySat)
+	// Denylist lists satellites to leave out.
+	Denylist []SatID
+
+	talker string
+	used   map[SatID]bool
+	sats   []GSVSatInfo
+	ids    []SatID // of sats
+	acc    GSVAccumulator
+}
+
+// HandleRaw satisfies RawHandler.
+func (s *SkyPlot) HandleRaw(line string) {
+	s.talker = talkerID(line)
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (s *SkyPlot) HandleEpoch(t time.Time) {
+	s.emit()
+}
+
+// HandleGSA satisfies GSAHandler.
+func (s *SkyPlot) HandleGSA(g GSA) {
+	if s.used == nil {
+		s.used = map[SatID]bool{}
+	}
+	for _, id := range g.SatIDs(s.talker) {
+		s.used[id] = true
+	}
+}
+
+// HandleGSV satisfies GSVHandler.
+func (s *SkyPlot) HandleGSV(g GSV) {
+	if s.acc.Add(g) {
+		for _, si := range allowedSats(s.acc.SatInfo, s.talker, s.Denylist) {
+			s.sats = append(s.sats, si)
+			s.ids = append(s.ids, si.ID(s.talker))
+		}
+	}
+}
+
+// Flush satisfies Flusher, plotting the last cycle.
+func (s *SkyPlot) Flush() {
+	s.emit()
+}
+
+func (s *SkyPlot) emit() {
+	if len(s.sats) > 0 && s.Plot != nil {
+		seen := map[SatID]bool{}
+		var sats []SkySat
+		for i, si := range s.sats {
+			if id := s.ids[i]; !seen[id] {
+				seen[id] = true
+				sats = append(sats, SkySat{si, s.used[id]})
+			}
+		}
+		s.Plot(sats)
+	}
+	s.used = nil
+	s.sats, s.ids = nil, nil
+	s.acc = GSVAccumulator{}
+}
@@ -0,0 +1,32 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkyPlot(t *testing.T) {
+	var plots [][]SkySat
+	s := &SkyPlot{Plot: func(sats []SkySat) { plots = append(plots, sats) }}
+	if err := Process(strings.NewReader(ubloxSample), s, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if len(plots) != 1 {
+		t.Fatalf("Expected one plot, got %v", plots)
+	}
+
+	// The sample lists 14 and 15 twice.
+	sats := plots[0]
+	if len(sats) != 12 {
+		t.Errorf("Expected 12 satellites, got %v", sats)
+	}
+	used := map[int]bool{25: true, 1: true, 22: true}
+	for _, sat := range sats {
+		if sat.Used != used[sat.PRN] {
+			t.Errorf("Expected PRN %v used=%v, got %+v", sat.PRN, used[sat.PRN], sat)
+		}
+	}
+	if sats[0] != (SkySat{GSVSatInfo{PRN: 25, Elevation: 15, Azimuth: 175, SNR: 30}, true}) {
+		t.Errorf("Unexpected first satellite: %+v", sats[0])
+	}
+}
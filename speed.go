@@ -0,0 +1,93 @@
+package nmea
+
+import (
+	"math"
+	"time"
+)
+
+// SpeedSmoother smooths the speed over ground reported by RMC or VTG
+// messages with an exponential moving average.
+//
+// Each fix cycle contributes a single speed: VTG's if the cycle has
+// one, otherwise RMC's.  Samples are weighted by the time between
+// cycles, so the average responds at the same rate regardless of the
+// receiver's update rate.
+type SpeedSmoother struct {
+	// Window is the time constant of the average: a sample's
+	// weight decays by a factor of e every Window.  Zero means ten
+	// seconds.
+	Window time.Duration
+
+	avg     float64
+	started bool
+	last    time.Time
+
+	epoch   time.Time
+	speed   float64
+	have    bool
+	fromVTG bool
+}
+
+// HandleEpoch satisfies EpochHandler.
+func (s *SpeedSmoother) HandleEpoch(t time.Time) {
+	s.commit()
+	s.epoch = t
+}
+
+// HandleRMC satisfies RMCHandler.
+func (s *SpeedSmoother) HandleRMC(r RMC) {
+	if r.Valid() && !s.fromVTG {
+		s.speed = r.Speed
+		s.have = true
+	}
+}
+
+// HandleVTG satisfies VTGHandler.
+func (s *SpeedSmoother) HandleVTG(v VTG) {
+	if v.Valid {
+		s.speed = v.Knots
+		s.have = true
+		s.fromVTG = true
+	}
+}
+
+// Flush satisfies Flusher, including the last cycle in the average.
+func (s *SpeedSmoother) Flush() {
+	s.commit()
+}
+
+// Current returns the smoothed speed in knots, including the cycle in
+// progress.
+func (s *SpeedSmoother) Current() float64 {
+	if !s.have {
+		return s.avg
+	}
+	return s.blend()
+}
+
+// blend returns the average including the pending speed.
+func (s *SpeedSmoother) blend() float64 {
+	if !s.started {
+		return s.speed
+	}
+	window := s.Window
+	if window == 0 {
+		window = 10 * time.Second
+	}
+	dt := time.Second
+	if !s.last.IsZero() && !s.epoch.IsZero() {
+		dt = timeBetween(s.last, s.epoch)
+	}
+	alpha := 1 - math.Exp(-float64(dt)/float64(window))
+	return s.avg + alpha*(s.speed-s.avg)
+}
+
+func (s *SpeedSmoother) commit() {
+	if s.have {
+		s.avg = s.blend()
+		s.started = true
+		s.last = s.epoch
+	}
+	s.have = false
+	s.fromVTG = false
+}
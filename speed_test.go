@@ -0,0 +1,60 @@
+package nmea
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// noisySpeeds returns a minute of fixes alternating between speeds
+// one knot either side of 5, with VTGs reporting twice the speed if
+// vtg is true.
+func noisySpeeds(vtg bool) string {
+	var lines []string
+	for i := 0; i < 60; i++ {
+		speed := 5 + float64(i%2*2-1)
+		lines = append(lines, AppendChecksum(fmt.Sprintf(
+			"$GPRMC,1623%02d.00,A,3723.02837,N,12159.39853,W,%.1f,188.36,110706,,,A", i, speed)))
+		if vtg {
+			lines = append(lines, AppendChecksum(fmt.Sprintf(
+				"$GPVTG,188.36,T,,M,%.1f,N,,K,A", speed*2)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSpeedSmoother(t *testing.T) {
+	tests := []struct {
+		vtg bool
+		exp float64
+	}{
+		{false, 5},
+		{true, 10},
+	}
+	for _, test := range tests {
+		s := &SpeedSmoother{}
+		if err := Process(strings.NewReader(noisySpeeds(test.vtg)), s, nil); err != nil {
+			t.Fatalf("Error processing: %v", err)
+		}
+		if got := s.Current(); math.Abs(got-test.exp) > 0.2 {
+			t.Errorf("With VTG=%v, expected a smoothed speed near %v, got %v", test.vtg, test.exp, got)
+		}
+	}
+}
+
+func TestSpeedSmootherFirstSample(t *testing.T) {
+	s := &SpeedSmoother{}
+	if got := s.Current(); got != 0 {
+		t.Errorf("Expected 0 before any fix, got %v", got)
+	}
+	s.HandleRMC(RMC{Status: 'A', Speed: 3})
+	if got := s.Current(); got != 3 {
+		t.Errorf("Expected the first speed, got %v", got)
+	}
+	s.HandleVTG(VTG{Knots: 4, Valid: true})
+	s.HandleRMC(RMC{Status: 'A', Speed: 3})
+	if got := s.Current(); got != 4 {
+		t.Errorf("Expected VTG's speed to be preferred, got %v", got)
+	}
+}
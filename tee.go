@@ -0,0 +1,55 @@
+package nmea
+
+import "io"
+
+// TeeHandler copies every sentence passing checksum validation to a
+// writer, for example to keep a log of a receiver's output, while
+// passing everything on to another handler.
+//
+// Sentences are written with a CRLF terminator as they arrive, so
+// the writer is free to rotate files between writes.
+type TeeHandler struct {
+	// W receives each sentence.
+	W io.Writer
+	// Handler receives the parsed sentences, as if it had been
+	// passed to Process directly.
+	Handler interface{}
+
+	err error
+}
+
+// HandleRaw satisfies RawHandler.
+func (t *TeeHandler) HandleRaw(line string) {
+	if t.err == nil {
+		_, t.err = io.WriteString(t.W, line+"\r\n")
+	}
+	if h, ok := t.Handler.(RawHandler); ok {
+		h.HandleRaw(line)
+	}
+}
+
+// Err returns the first error writing to W, if any.  Nothing more
+// is written after an error.
+func (t *TeeHandler) Err() error {
+	return t.err
+}
+
+func (t *TeeHandler) wrapped() interface{} {
+	return t.Handler
+}
+
+// A wrapper is a handler passing sentences on to another handler.
+type wrapper interface {
+	wrapped() interface{}
+}
+
+// unwrap returns the innermost handler wrapped by h.
+func unwrap(h interface{}) interface{} {
+	for {
+		w, ok := h.(wrapper)
+		if !ok {
+			return h
+		}
+		h = w.wrapped()
+	}
+}
@@ -0,0 +1,48 @@
+package nmea
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTeeHandler(t *testing.T) {
+	input := strings.Replace(ubloxSample, "\n", "\r\n", -1)
+	buf := &strings.Builder{}
+	inner := &struct {
+		testUnion
+		DuplicateDetector
+	}{}
+	h := &TeeHandler{W: buf, Handler: inner}
+	if err := Process(strings.NewReader(input), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if h.Err() != nil {
+		t.Errorf("Unexpected write error: %v", h.Err())
+	}
+	if buf.String() != input {
+		t.Errorf("Expected tee output\n%q\ngot\n%q", input, buf.String())
+	}
+	if inner.gsv.SentenceNum != 4 || inner.zda.Timestamp.IsZero() {
+		t.Errorf("Expected the whole sample to be handled, got %#v", inner.testUnion)
+	}
+	if inner.last["RMC"] == "" {
+		t.Errorf("Expected raw sentences to be passed on")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestTeeHandlerError(t *testing.T) {
+	h := &TeeHandler{W: failingWriter{}, Handler: &testUnion{}}
+	if err := Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing: %v", err)
+	}
+	if h.Err() == nil {
+		t.Errorf("Expected write error")
+	}
+}
@@ -0,0 +1,43 @@
+package nmea
+
+import "time"
+
+// A TrackPoint is a single timestamped point of a track.
+type TrackPoint struct {
+	Time      time.Time `json:"time"`
+	Latitude  float64   `json:"lat"`
+	Longitude float64   `json:"lon"`
+	// Altitude is in meters above mean sea level, and is only
+	// known once merged with a GGA (see MergeGGA).
+	Altitude float64 `json:"alt,omitempty"`
+	// Speed is the speed over ground in knots.
+	Speed float64 `json:"speed"`
+	// Course is the true course over ground in degrees.
+	Course float64 `json:"course"`
+}
+
+// TrackPoint returns the track point described by r.
+func (r RMC) TrackPoint() TrackPoint {
+	return TrackPoint{
+		Time:      r.Timestamp,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Speed:     r.Speed,
+		Course:    r.Angle,
+	}
+}
+
+// MergeGGA fills in the altitude from a GGA of the same epoch.
+//
+// GGA carries only a time of day, so the merge happens only if it
+// matches the time of day of the track point.  MergeGGA reports
+// whether the merge happened.
+func (t *TrackPoint) MergeGGA(g GGA) bool {
+	h1, m1, s1 := t.Time.Clock()
+	h2, m2, s2 := g.Taken.Clock()
+	if h1 != h2 || m1 != m2 || s1 != s2 || t.Time.Nanosecond() != g.Taken.Nanosecond() {
+		return false
+	}
+	t.Altitude = g.Altitude
+	return true
+}
@@ -0,0 +1,49 @@
+package nmea
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRMCTrackPoint(t *testing.T) {
+	rh := &rmcHandler{}
+	gh := &ggaHandler{}
+	h := &struct {
+		*rmcHandler
+		*ggaHandler
+	}{rh, gh}
+	if err := Process(strings.NewReader(ubloxSample), h, nil); err != nil {
+		t.Fatalf("Error processing sample: %v", err)
+	}
+
+	tp := rh.rmc.TrackPoint()
+	exp := TrackPoint{
+		Time:      time.Date(2006, 7, 11, 16, 22, 54, 0, time.UTC),
+		Latitude:  37.383806166666666,
+		Longitude: -121.9899755,
+		Speed:     0.82,
+		Course:    188.36,
+	}
+	if !similar(t, tp, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", tp, exp)
+	}
+
+	if !tp.MergeGGA(gh.gga) {
+		t.Errorf("Expected GGA from the same epoch to merge")
+	}
+	exp.Altitude = 525.6
+	if !similar(t, tp, exp) {
+		t.Errorf("Expected more similarity between %#v and (wanted) %#v", tp, exp)
+	}
+
+	other := gh.gga
+	other.Taken = other.Taken.Add(time.Second)
+	other.Altitude = 1
+	if tp.MergeGGA(other) {
+		t.Errorf("Expected GGA from another epoch not to merge")
+	}
+	if tp.Altitude != 525.6 {
+		t.Errorf("Altitude changed by unmerged GGA: %v", tp.Altitude)
+	}
+}
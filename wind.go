@@ -0,0 +1,25 @@
+package nmea
+
+import "math"
+
+// TrueWind computes the true wind from the apparent wind measured on a
+// moving vessel.
+//
+// boatHeading is in degrees true, and apparentAngle is the angle the
+// apparent wind comes from, clockwise from the bow in degrees (so
+// port is 180-360, as in an MWV, or a VWR with L negated).  The
+// speeds may be in any unit, as long as it's the same for both.
+//
+// trueAngle is the direction the true wind comes from in degrees true
+// ([0,360)), and trueSpeed is in the unit of the inputs.
+func TrueWind(boatSpeed, boatHeading, apparentAngle, apparentSpeed float64) (trueAngle, trueSpeed float64) {
+	// The boat's motion adds a wind from dead ahead at its own
+	// speed, which is subtracted from the apparent wind.
+	θ := d2r(apparentAngle)
+	u := apparentSpeed*math.Cos(θ) - boatSpeed
+	v := apparentSpeed * math.Sin(θ)
+
+	trueSpeed = math.Hypot(u, v)
+	trueAngle = math.Mod(boatHeading+r2d(math.Atan2(v, u))+720, 360)
+	return trueAngle, trueSpeed
+}